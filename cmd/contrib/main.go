@@ -2,17 +2,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"unicode"
 
 	"github.com/1Password/shell-plugins/plugins"
+	"github.com/1Password/shell-plugins/sdk/manifest"
 	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/scan"
 	"github.com/1Password/shell-plugins/sdk/schema"
 	"github.com/1Password/shell-plugins/sdk/schema/credname"
 	"github.com/AlecAivazis/survey/v2"
@@ -22,6 +26,22 @@ const exampleSecretsCommandSuffix = "example-secrets"
 const validateCommandSuffix = "validate"
 const existsCommandSuffix = "exists"
 
+// behaviorFlag makes the validate commands also run the provisioners and importers against example items.
+const behaviorFlag = "--behavior"
+
+// formatFlag sets the output format of the validate commands, text (default), json, junit or sarif, and of the
+// manifest command, json (default) or yaml.
+const formatFlag = "--format"
+
+// configFlag sets the path of the validation config, with suppressions and severity overrides.
+const configFlag = "--config"
+
+// strictFlag makes the validate commands treat warnings as errors.
+const strictFlag = "--strict"
+
+// defaultValidationConfigPath is used as the validation config if it exists and no other config is specified.
+const defaultValidationConfigPath = "validation.yaml"
+
 func main() {
 	command := os.Args[1]
 	isPlugin, pluginName, pluginCommand := isPluginCommand(command)
@@ -38,7 +58,18 @@ func main() {
 		}
 
 		if strings.HasSuffix(pluginCommand, validateCommandSuffix) {
-			plugintest.PrintValidationReport(plugin)
+			reports := plugin.DeepValidate()
+			if hasFlag(behaviorFlag) {
+				reports = append(reports, plugins.ValidateBehavior(plugin)...)
+			}
+
+			results := validationConfig().Apply([]plugintest.PluginValidationReports{
+				{Plugin: plugin.Name, Reports: reports},
+			})
+			err := plugintest.WriteValidationReports(os.Stdout, outputFormat(), results)
+			if err != nil {
+				log.Fatal(err)
+			}
 			return
 		}
 
@@ -48,14 +79,40 @@ func main() {
 	}
 
 	if command == validateCommandSuffix {
-		var shouldExitWithError bool
+		var results []plugintest.PluginValidationReports
+		registryReports := plugins.ValidateRegistry(plugins.List())
 		for _, plugin := range plugins.List() {
-			isReportPrinted := plugintest.PrintReportIfErrors(plugin)
-			if isReportPrinted {
-				shouldExitWithError = true
+			reports := append(plugin.DeepValidate(), registryReports[plugin.Name])
+			if hasFlag(behaviorFlag) {
+				reports = append(reports, plugins.ValidateBehavior(plugin)...)
+			}
+			results = append(results, plugintest.PluginValidationReports{Plugin: plugin.Name, Reports: reports})
+		}
+		results = validationConfig().Apply(results)
+
+		var shouldExitWithError bool
+		var resultsWithErrors []plugintest.PluginValidationReports
+		for _, result := range results {
+			for _, report := range result.Reports {
+				if report.HasErrors() {
+					resultsWithErrors = append(resultsWithErrors, result)
+					shouldExitWithError = true
+					break
+				}
 			}
 		}
 
+		// The text output only shows the plugins with errors, to not drown them out
+		format := outputFormat()
+		if format == plugintest.ValidationOutputText {
+			results = resultsWithErrors
+		}
+
+		err := plugintest.WriteValidationReports(os.Stdout, format, results)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		if shouldExitWithError {
 			os.Exit(1)
 		}
@@ -70,6 +127,31 @@ func main() {
 		return
 	}
 
+	if command == "manifest" {
+		m, err := manifest.New(context.Background(), plugins.List())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		format, _ := flagValue(formatFlag)
+		err = m.Write(os.Stdout, manifest.Format(format))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if command == "scan" {
+		foundSecrets, err := scanForSecrets(os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if foundSecrets {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if command == "new-plugin" {
 		err := newPlugin()
 		if err != nil {
@@ -79,6 +161,57 @@ func main() {
 	}
 }
 
+func hasFlag(flag string) bool {
+	for _, arg := range os.Args[2:] {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns the value of a flag passed as either "--flag=value" or "--flag value".
+func flagValue(flag string) (string, bool) {
+	args := os.Args[2:]
+	for i, arg := range args {
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"="), true
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+func outputFormat() plugintest.ValidationOutputFormat {
+	if format, ok := flagValue(formatFlag); ok {
+		return plugintest.ValidationOutputFormat(format)
+	}
+	return plugintest.ValidationOutputText
+}
+
+// validationConfig loads the validation config specified with the config flag, or the default one if it exists.
+func validationConfig() plugintest.ValidationConfig {
+	var config plugintest.ValidationConfig
+
+	path, ok := flagValue(configFlag)
+	if !ok {
+		path = defaultValidationConfigPath
+	}
+	if _, err := os.Stat(path); ok || err == nil {
+		config, err = plugintest.LoadValidationConfig(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if hasFlag(strictFlag) {
+		config.Strict = true
+	}
+	return config
+}
+
 func isPluginCommand(command string) (isPluginCommand bool, pluginName string, pluginCommand string) {
 	chunks := strings.Split(command, "/")
 	if len(chunks) < 2 {
@@ -87,6 +220,103 @@ func isPluginCommand(command string) (isPluginCommand bool, pluginName string, p
 	return true, chunks[0], chunks[1]
 }
 
+// scanForSecrets scans the specified files and directories, or the current directory if none are specified, for credentials
+// of the registered plugins. Passing --git-diff scans the lines added in the working tree and the untracked files that
+// are not ignored instead, and --git-diff=<revision range> the lines added in that range. Returns whether any
+// credentials were found.
+func scanForSecrets(args []string) (bool, error) {
+	scanner := scan.NewScanner(plugins.List())
+
+	var findings []scan.Finding
+	var paths []string
+	for _, arg := range args {
+		if arg != "--git-diff" && !strings.HasPrefix(arg, "--git-diff=") {
+			paths = append(paths, arg)
+			continue
+		}
+
+		gitArgs := []string{"diff", "--no-color", "--no-ext-diff", "--unified=0"}
+		if revisionRange := strings.TrimPrefix(strings.TrimPrefix(arg, "--git-diff"), "="); revisionRange != "" {
+			gitArgs = append(gitArgs, revisionRange)
+		} else {
+			gitArgs = append(gitArgs, "HEAD")
+
+			// New files don't show up in the diff until they're added to the index
+			untrackedFindings, err := scanUntrackedFiles(scanner)
+			if err != nil {
+				return false, err
+			}
+			findings = append(findings, untrackedFindings...)
+		}
+
+		diff, err := exec.Command("git", gitArgs...).Output()
+		if err != nil {
+			return false, fmt.Errorf("running git diff: %w", err)
+		}
+
+		diffFindings, err := scanner.ScanDiff(bytes.NewReader(diff))
+		if err != nil {
+			return false, err
+		}
+		findings = append(findings, diffFindings...)
+	}
+
+	if len(args) == 0 {
+		paths = []string{"."}
+	}
+
+	for _, path := range paths {
+		pathFindings, err := scanner.ScanPath(path)
+		if err != nil {
+			return false, err
+		}
+		findings = append(findings, pathFindings...)
+	}
+
+	for _, finding := range findings {
+		likely := finding.Likely()
+		fmt.Printf("%s:%d:%d: possible %s %s (field '%s', entropy %.2f): %s\n", finding.Path, finding.Line, finding.Column, likely.Plugin, likely.Credential, likely.Field, finding.Entropy, finding.Redacted)
+		for _, candidate := range finding.Candidates[1:] {
+			fmt.Printf("\tor %s %s (field '%s')\n", candidate.Plugin, candidate.Credential, candidate.Field)
+		}
+	}
+
+	return len(findings) > 0, nil
+}
+
+// scanUntrackedFiles scans the files in the working tree that are not tracked by git and not ignored. The paths of the
+// findings are relative to the root of the repository, like the paths in the diff.
+func scanUntrackedFiles(scanner *scan.Scanner) ([]scan.Finding, error) {
+	root, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("running git rev-parse: %w", err)
+	}
+
+	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z")
+	cmd.Dir = strings.TrimSpace(string(root))
+	untracked, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running git ls-files: %w", err)
+	}
+
+	var findings []scan.Finding
+	for _, path := range strings.Split(string(untracked), "\x00") {
+		if path == "" {
+			continue
+		}
+
+		pathFindings, err := scanner.ScanPath(filepath.Join(cmd.Dir, path))
+		if err != nil {
+			return nil, err
+		}
+		for i := range pathFindings {
+			pathFindings[i].Path = path
+		}
+		findings = append(findings, pathFindings...)
+	}
+	return findings, nil
+}
+
 func newPlugin() error {
 	var questionnaire = []*survey.Question{
 		{
@@ -2,8 +2,7 @@ package aws
 
 import (
 	"context"
-	"os"
-	"strings"
+	"fmt"
 
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/importer"
@@ -24,7 +23,8 @@ func AccessKey() schema.CredentialType {
 				MarkdownDescription: "The ID of the access key used to authenticate to AWS.",
 				Composition: &schema.ValueComposition{
 					Length: 20,
-					Prefix: "AKIA",
+					// Long-term access keys start with AKIA, temporary ones from STS with ASIA
+					Prefixes: []string{"AKIA", "ASIA"},
 					Charset: schema.Charset{
 						Uppercase: true,
 						Digits:    true,
@@ -41,6 +41,7 @@ func AccessKey() schema.CredentialType {
 						Uppercase: true,
 						Lowercase: true,
 						Digits:    true,
+						Specific:  []rune{'/', '+'},
 					},
 				},
 			},
@@ -48,17 +49,46 @@ func AccessKey() schema.CredentialType {
 				Name:                fieldname.DefaultRegion,
 				MarkdownDescription: "The default region to use for this access key.",
 				Optional:            true,
+				Placeholder:         "us-east-1",
+				Aliases:             []sdk.FieldName{fieldname.Region},
 			},
 			{
 				Name:                fieldname.OneTimePassword,
-				MarkdownDescription: "The one-time code value for MFA authentication.",
+				MarkdownDescription: "The one-time code value for MFA authentication, or the TOTP setup to generate it from.",
+				Secret:              true,
 				Optional:            true,
+				Type:                schema.FieldTypeTOTP,
 			},
 			{
 				Name:                fieldname.MFASerial,
 				MarkdownDescription: "ARN of the MFA serial number to use to generate temporary STS credentials if the item contains a TOTP setup.",
 				Optional:            true,
 			},
+			{
+				Name:                fieldname.Profile,
+				MarkdownDescription: "Name of the AWS profile to use this access key for. Separate multiple profiles with commas. If not set, the access key is used for every profile. Profiles in the AWS config file that assume a role with one of these profiles as `source_profile` use this access key as well.",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.RoleARN,
+				MarkdownDescription: "ARN of the IAM role to assume. Separate multiple ARNs with commas to assume them one after the other (role chaining).",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.ExternalID,
+				MarkdownDescription: "External ID to pass when assuming the role, if required by the role's trust policy.",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.SessionName,
+				MarkdownDescription: "Name of the role session. Defaults to '1password-shell-plugin'.",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.Duration,
+				MarkdownDescription: "How long the temporary credentials should last, e.g. '3600' or '1h'. Defaults to 15 minutes for MFA sessions and 1 hour for roles.",
+				Optional:            true,
+			},
 		},
 		DefaultProvisioner: AWSProvisioner(),
 		Importer: importer.TryAll(
@@ -89,64 +119,138 @@ var defaultEnvVarMapping = map[string]sdk.FieldName{
 	"AWS_DEFAULT_REGION":    fieldname.DefaultRegion,
 }
 
-// TryCredentialsFile looks for the access key in the ~/.aws/credentials file.
+// TryCredentialsFile looks for access keys in the ~/.aws/credentials file, or the file set in AWS_SHARED_CREDENTIALS_FILE,
+// combined with the profile settings in the ~/.aws/config file, or the file set in AWS_CONFIG_FILE. Every profile with
+// static access keys or an assumable role becomes a separate candidate. Profiles that rely on other credential providers,
+// such as SSO, can't be imported and are reported as warnings.
 func TryCredentialsFile() sdk.Importer {
-	return importer.TryFile("~/.aws/credentials", func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
-		credentialsFile, err := contents.ToINI()
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		credentialsPath := in.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+		if credentialsPath == "" {
+			credentialsPath = "~/.aws/credentials"
+		}
+
+		configPath := in.Getenv("AWS_CONFIG_FILE")
+		if configPath == "" {
+			configPath = "~/.aws/config"
+		}
+
+		attempt := out.NewAttempt(importer.SourceFile(credentialsPath))
+
+		credentialsFile, err := loadINIFile(in, credentialsPath)
 		if err != nil {
-			out.AddError(err)
+			attempt.AddError(err)
 			return
 		}
 
-		// Read config file from the location set in AWS_CONFIG_FILE env var or from  ~/.aws/config
-		configPath := os.Getenv("AWS_CONFIG_FILE")
-		if configPath != "" {
-			if strings.HasPrefix(configPath, "~") {
-				configPath = in.FromHomeDir(configPath[1:])
-			} else {
-				configPath = in.FromRootDir(configPath)
-			}
-		} else {
-			configPath = in.FromHomeDir(".aws", "config") // default config file location
+		configFile, err := loadINIFile(in, configPath)
+		if err != nil {
+			attempt.AddError(err)
 		}
-		var configFile *ini.File
-		configContent, err := os.ReadFile(configPath)
-		if err != nil && !os.IsNotExist(err) {
-			out.AddError(err)
+
+		if credentialsFile == nil && configFile == nil {
+			return
 		}
-		configFile, err = importer.FileContents(configContent).ToINI()
-		if err != nil {
-			out.AddError(err)
+
+		for _, profileName := range listProfiles(credentialsFile, configFile) {
+			importProfile(profileName, credentialsFile, configFile, attempt)
 		}
+	}
+}
 
-		for _, section := range credentialsFile.Sections() {
-			profileName := section.Name()
-			fields := make(map[sdk.FieldName]string)
-			if section.HasKey("aws_access_key_id") && section.Key("aws_access_key_id").Value() != "" {
-				fields[fieldname.AccessKeyID] = section.Key("aws_access_key_id").Value()
-			}
+// importProfile adds an import candidate for the specified profile, if it can be represented as an access key.
+func importProfile(profileName string, credentialsFile *ini.File, configFile *ini.File, out *sdk.ImportAttempt) {
+	configSection := getConfigSectionByProfile(configFile, profileName)
 
-			if section.HasKey("aws_secret_access_key") && section.Key("aws_secret_access_key").Value() != "" {
-				fields[fieldname.SecretAccessKey] = section.Key("aws_secret_access_key").Value()
-			}
+	var fields map[sdk.FieldName]string
+	switch {
+	case hasSetting(configSection, "role_arn"):
+		if hasSetting(configSection, "credential_source") || hasSetting(configSection, "web_identity_token_file") {
+			out.AddWarning(fmt.Sprintf("AWS profile '%s' assumes a role using credentials from the environment, which can't be imported", profileName))
+			return
+		}
 
-			// read profile configuration from config file
-			if configFile != nil {
-				configSection := getConfigSectionByProfile(configFile, profileName)
-				if configSection != nil {
-					if configSection.HasKey("region") && configSection.Key("region").Value() != "" {
-						fields[fieldname.DefaultRegion] = configSection.Key("region").Value()
-					}
-				}
-			}
+		sourceProfile := settingValue(configSection, "source_profile")
+		fields = staticAccessKey(sourceProfile, credentialsFile, configFile)
+		if fields == nil {
+			out.AddWarning(fmt.Sprintf("AWS profile '%s' assumes a role, but its source profile '%s' has no access key", profileName, sourceProfile))
+			return
+		}
 
-			// add only candidates with required credential fields
-			if fields[fieldname.AccessKeyID] != "" && fields[fieldname.SecretAccessKey] != "" {
-				out.AddCandidate(sdk.ImportCandidate{
-					Fields:   fields,
-					NameHint: importer.SanitizeNameHint(profileName),
-				})
+		for key, fieldName := range roleSettingsMapping {
+			if value := settingValue(configSection, key); value != "" {
+				fields[fieldName] = value
 			}
 		}
+	case hasSetting(configSection, "sso_start_url") || hasSetting(configSection, "sso_session"):
+		out.AddWarning(fmt.Sprintf("AWS profile '%s' uses IAM Identity Center (SSO), which can't be imported as an access key", profileName))
+		return
+	case hasSetting(configSection, "credential_process"):
+		out.AddWarning(fmt.Sprintf("AWS profile '%s' uses an external credential process, which can't be imported", profileName))
+		return
+	default:
+		fields = staticAccessKey(profileName, credentialsFile, configFile)
+		if fields == nil {
+			// Profiles without credentials only contain settings, which are not worth importing
+			return
+		}
+
+		if value := settingValue(configSection, "mfa_serial"); value != "" {
+			fields[fieldname.MFASerial] = value
+		}
+	}
+
+	if value := settingValue(configSection, "region"); value != "" {
+		fields[fieldname.DefaultRegion] = value
+	}
+
+	// link non-default profiles to the item, so the item gets used when the profile is requested
+	if profileName != "default" {
+		fields[fieldname.Profile] = profileName
+	}
+
+	out.AddCandidate(sdk.ImportCandidate{
+		Fields:   fields,
+		NameHint: importer.SanitizeNameHint(profileName),
 	})
 }
+
+var roleSettingsMapping = map[string]sdk.FieldName{
+	"role_arn":          fieldname.RoleARN,
+	"external_id":       fieldname.ExternalID,
+	"role_session_name": fieldname.SessionName,
+	"duration_seconds":  fieldname.Duration,
+	"mfa_serial":        fieldname.MFASerial,
+}
+
+// staticAccessKey returns the access key fields of the specified profile, which can be set in either the
+// credentials file or the config file. Returns nil if the profile doesn't have a complete access key.
+func staticAccessKey(profileName string, credentialsFile *ini.File, configFile *ini.File) map[sdk.FieldName]string {
+	if profileName == "" {
+		return nil
+	}
+
+	sections := []*ini.Section{getConfigSectionByProfile(configFile, profileName)}
+	if credentialsFile != nil {
+		if section, err := credentialsFile.GetSection(profileName); err == nil {
+			// The credentials file takes precedence over the config file
+			sections = append(sections, section)
+		}
+	}
+
+	fields := make(map[sdk.FieldName]string)
+	for _, section := range sections {
+		if value := settingValue(section, "aws_access_key_id"); value != "" {
+			fields[fieldname.AccessKeyID] = value
+		}
+		if value := settingValue(section, "aws_secret_access_key"); value != "" {
+			fields[fieldname.SecretAccessKey] = value
+		}
+	}
+
+	if fields[fieldname.AccessKeyID] == "" || fields[fieldname.SecretAccessKey] == "" {
+		return nil
+	}
+
+	return fields
+}
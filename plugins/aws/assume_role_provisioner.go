@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/otp"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+const (
+	defaultSessionName     = "1password-shell-plugin"
+	defaultRoleDuration    = 1 * time.Hour
+	assumeRoleCacheKeyBase = "sts-assume-role"
+)
+
+// AssumeRoleProvisioner provisions temporary credentials for an IAM role, obtained by calling sts:AssumeRole
+// with the access key from the item. When multiple role ARNs are specified, the roles are assumed one after the
+// other, each using the credentials of the previous role (role chaining).
+type AssumeRoleProvisioner struct {
+	RoleARNs    []string
+	ExternalID  string
+	SessionName string
+	Duration    time.Duration
+
+	// (Optional) MFA to use when assuming the first role in the chain. TOTPCode can be the current MFA code or the
+	// TOTP setup to generate it from: an otpauth:// URI or base32 encoded secret.
+	TOTPCode  string
+	MFASerial string
+
+	// (Optional) Influences how the MFA code is generated if TOTPCode is a TOTP setup.
+	OTPOptions []otp.Option
+
+	// (Optional) Cached credentials with a shorter remaining lifetime than this get refreshed. Defaults to 5 minutes.
+	RefreshThreshold time.Duration
+
+	// (Optional) Creates the client used to call STS. Defaults to DefaultSTSClient.
+	NewClient STSClientFactory
+}
+
+// parseRoleARNs splits the Role ARN field into the roles to assume. Multiple roles can be separated by
+// commas or newlines to chain them.
+func parseRoleARNs(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' '
+	})
+}
+
+// cacheKey keys the cache by the source access key and all parameters passed to AssumeRole, so that changing any of
+// them doesn't reuse credentials that were obtained for another principal or with other settings. The parameters are
+// hashed, since the external ID is meant to be kept private.
+func (p AssumeRoleProvisioner) cacheKey(accessKeyID string) string {
+	params := []string{
+		accessKeyID,
+		strings.Join(p.RoleARNs, ","),
+		p.ExternalID,
+		p.SessionName,
+		p.Duration.String(),
+		p.MFASerial,
+	}
+	hash := sha256.Sum256([]byte(strings.Join(params, "\n")))
+	return fmt.Sprintf("%s:%s", assumeRoleCacheKeyBase, hex.EncodeToString(hash[:]))
+}
+
+func (p AssumeRoleProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	region, err := getRegion(in)
+	if err != nil {
+		out.AddError(err)
+		return
+	}
+
+	cacheKey := p.cacheKey(in.ItemFields[fieldname.AccessKeyID])
+	if cached, ok := getCachedCredentials(in.Cache, cacheKey, p.RefreshThreshold); ok {
+		addSTSCredentials(out, cached)
+		out.AddEnvVar("AWS_DEFAULT_REGION", region)
+		return
+	}
+
+	sessionName := p.SessionName
+	if sessionName == "" {
+		sessionName = defaultSessionName
+	}
+
+	duration := p.Duration
+	if duration == 0 {
+		duration = defaultRoleDuration
+	}
+
+	config := aws.NewConfig()
+	config.Region = region
+	config.Credentials = credentials.NewStaticCredentialsProvider(in.ItemFields[fieldname.AccessKeyID], in.ItemFields[fieldname.SecretAccessKey], "")
+
+	var creds *types.Credentials
+	for i, roleARN := range p.RoleARNs {
+		input := &sts.AssumeRoleInput{
+			RoleArn:         aws.String(roleARN),
+			RoleSessionName: aws.String(sessionName),
+			DurationSeconds: aws.Int32(int32(duration.Seconds())),
+		}
+
+		if p.ExternalID != "" {
+			input.ExternalId = aws.String(p.ExternalID)
+		}
+
+		// MFA only applies to the first role: the roles after that are assumed using temporary credentials
+		if i == 0 && p.MFASerial != "" && p.TOTPCode != "" {
+			code, err := otp.Generate(ctx, p.TOTPCode, p.OTPOptions...)
+			if err != nil {
+				out.AddError(fmt.Errorf("generating MFA code: %w", err))
+				return
+			}
+			input.SerialNumber = aws.String(p.MFASerial)
+			input.TokenCode = aws.String(code)
+		}
+
+		// AWS limits chained role sessions to 1 hour
+		if i > 0 && duration > time.Hour {
+			input.DurationSeconds = aws.Int32(int32(time.Hour.Seconds()))
+		}
+
+		result, err := p.NewClient.newClient(*config).AssumeRole(ctx, input)
+		if err != nil {
+			out.AddError(fmt.Errorf("assuming role %s: %w", roleARN, err))
+			return
+		}
+
+		creds = result.Credentials
+		config.Credentials = credentials.NewStaticCredentialsProvider(*creds.AccessKeyId, *creds.SecretAccessKey, *creds.SessionToken)
+	}
+
+	if creds == nil {
+		out.AddError(fmt.Errorf("no role ARN specified"))
+		return
+	}
+
+	addSTSCredentials(out, *creds)
+	out.AddEnvVar("AWS_DEFAULT_REGION", region)
+
+	err = out.Cache.Put(cacheKey, *creds, *creds.Expiration)
+	if err != nil {
+		out.AddError(fmt.Errorf("failed to serialize aws sts credentials: %w", err))
+	}
+}
+
+func (p AssumeRoleProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	// Nothing to do here: environment variables get wiped automatically when the process exits.
+}
+
+func (p AssumeRoleProvisioner) Description() string {
+	return "Provision environment variables with temporary credentials from an assumed IAM role: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN"
+}
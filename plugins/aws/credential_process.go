@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// The credential process mode allows tools that read ~/.aws/config, such as Terraform, the AWS SDKs and boto3,
+// to use the access key stored in 1Password, by adding the following to a profile in ~/.aws/config:
+//
+//	credential_process = op plugin run -- aws credential-process
+//
+// The credentials get provisioned in the usual way (including the MFA and assume-role workflows and their caches),
+// after which the command line gets rewritten to have the AWS CLI print them in the credential_process JSON format:
+// Version 1, AccessKeyId, SecretAccessKey, SessionToken and Expiration.
+//
+// This requires AWS CLI v2, since `aws configure export-credentials` doesn't exist in v1. The installed version gets
+// checked before provisioning, so that v1 users get a clear error instead of an unknown command.
+
+const credentialProcessCommand = "credential-process"
+
+var exportCredentialsArgs = []string{"configure", "export-credentials", "--format", "process"}
+
+// isCredentialProcessMode reports whether the AWS CLI is invoked as credential process.
+func isCredentialProcessMode(commandLine []string) bool {
+	return len(commandLine) == 2 && commandLine[1] == credentialProcessCommand
+}
+
+// toCredentialProcessCommand rewrites the command line to print the provisioned credentials
+// in the credential_process JSON format.
+func toCredentialProcessCommand(commandLine []string) []string {
+	return append([]string{commandLine[0]}, exportCredentialsArgs...)
+}
+
+// cliVersionFunc returns the version output of the AWS CLI executable, e.g. "aws-cli/2.13.0 Python/3.11.4 Linux/6.2.0".
+type cliVersionFunc func(ctx context.Context, executable string) (string, error)
+
+func awsCLIVersion(ctx context.Context, executable string) (string, error) {
+	// AWS CLI v1 prints its version to stderr on older Python versions, so both outputs are read
+	output, err := exec.CommandContext(ctx, executable, "--version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// checkCredentialProcessSupport returns an error if the executable is AWS CLI v1, which can't export credentials.
+// If the version can't be determined, the command is run anyway and the AWS CLI reports what's wrong itself.
+func checkCredentialProcessSupport(ctx context.Context, executable string, cliVersion cliVersionFunc) error {
+	if cliVersion == nil {
+		cliVersion = awsCLIVersion
+	}
+
+	version, err := cliVersion(ctx, executable)
+	if err != nil || !strings.HasPrefix(version, "aws-cli/1.") {
+		return nil
+	}
+	return fmt.Errorf("using the AWS CLI as credential process requires AWS CLI v2, but %s is installed: upgrade to AWS CLI v2", strings.Fields(version)[0])
+}
@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"gopkg.in/ini.v1"
+)
+
+const profileFlag = "--profile"
+
+// globalOptionsWithValue are the global options of the AWS CLI that take a value. They're needed to tell
+// the values of global options apart from the service name.
+var globalOptionsWithValue = map[string]bool{
+	"--profile":             true,
+	"--region":              true,
+	"--output":              true,
+	"--endpoint-url":        true,
+	"--query":               true,
+	"--color":               true,
+	"--ca-bundle":           true,
+	"--cli-read-timeout":    true,
+	"--cli-connect-timeout": true,
+	"--cli-binary-format":   true,
+}
+
+// globalProfileArg looks for the --profile flag among the global options, which come before the service
+// name, e.g. "aws --profile prod s3 ls". Arguments after the service name belong to the command being run,
+// so a --profile there is left alone. Returns the profile and the arguments the flag spans.
+func globalProfileArg(commandLine []string) (profile string, start int, end int, found bool) {
+	for i := 1; i < len(commandLine); i++ {
+		arg := commandLine[i]
+		if !strings.HasPrefix(arg, "-") {
+			// Reached the service name
+			break
+		}
+
+		if arg == profileFlag && i+1 < len(commandLine) {
+			return commandLine[i+1], i, i + 2, true
+		}
+		if strings.HasPrefix(arg, profileFlag+"=") {
+			return strings.TrimPrefix(arg, profileFlag+"="), i, i + 1, true
+		}
+
+		if globalOptionsWithValue[arg] {
+			i++ // also skip the value
+		}
+	}
+
+	return "", 0, 0, false
+}
+
+// requestedProfile returns the name of the AWS profile the user asked for, either through the global --profile
+// flag or the AWS_PROFILE environment variable. The flag takes precedence, like it does in the AWS CLI.
+func requestedProfile(in sdk.ProvisionInput, commandLine []string) string {
+	if profile, _, _, found := globalProfileArg(commandLine); found {
+		return profile
+	}
+
+	return in.Getenv("AWS_PROFILE")
+}
+
+// stripProfileArgs removes the global --profile flag and its value from the command line. Credentials from a
+// profile passed with --profile take precedence over the credentials provisioned as environment variables, so
+// the flag has to be removed for the item's credentials to be used.
+func stripProfileArgs(commandLine []string) []string {
+	_, start, end, found := globalProfileArg(commandLine)
+	if !found {
+		return commandLine
+	}
+
+	result := append([]string{}, commandLine[:start]...)
+	return append(result, commandLine[end:]...)
+}
+
+// itemMatchesProfile reports whether the item can be used for the specified profile. Items that don't have
+// any profiles listed in the Profile field can be used for every profile.
+func itemMatchesProfile(itemFields map[sdk.FieldName]string, profile string) bool {
+	profiles, ok := itemFields[fieldname.Profile]
+	if !ok || strings.TrimSpace(profiles) == "" {
+		return true
+	}
+
+	for _, p := range strings.Split(profiles, ",") {
+		if strings.TrimSpace(p) == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyProfile maps the requested profile to the item and makes sure the AWS CLI doesn't fail looking
+// for a profile that only exists in 1Password. Returns the item fields to provision for the profile.
+//
+// The requested profile is mapped to the item if it's listed in the item's Profile field. A profile that is
+// configured locally to assume a role, using a source profile that maps to the item, maps to the item as well:
+// the role from the local config then gets assumed with the item's access key, like the AWS CLI would do.
+func applyProfile(in sdk.ProvisionInput, out *sdk.ProvisionOutput) (map[sdk.FieldName]string, error) {
+	profile := requestedProfile(in, out.CommandLine)
+	if profile == "" {
+		return in.ItemFields, nil
+	}
+
+	configFile, credentialsFile := loadProfileFiles(in)
+
+	itemFields := in.ItemFields
+	if roleFields, ok := roleProfileFields(in.ItemFields, getConfigSectionByProfile(configFile, profile)); ok {
+		itemFields = roleFields
+	} else if !itemMatchesProfile(in.ItemFields, profile) {
+		return nil, fmt.Errorf("the 1Password item is not configured for AWS profile '%s': add it to the '%s' field or select a different item", profile, fieldname.Profile)
+	}
+
+	out.CommandLine = stripProfileArgs(out.CommandLine)
+
+	// Keep using the profile's non-credential settings (e.g. region or output format) if the profile is
+	// configured locally. The provisioned environment variables take precedence over its credentials.
+	if profileExists(configFile, credentialsFile, profile) {
+		out.AddEnvVar("AWS_PROFILE", profile)
+	}
+
+	return itemFields, nil
+}
+
+// roleProfileFields returns the item fields with the role settings of the profile added, if the profile assumes
+// a role using a source profile that maps to the item. Items that assume a role themselves are used as they are.
+func roleProfileFields(itemFields map[sdk.FieldName]string, profileSection *ini.Section) (map[sdk.FieldName]string, bool) {
+	if itemFields[fieldname.RoleARN] != "" || !hasSetting(profileSection, "role_arn") {
+		return nil, false
+	}
+
+	sourceProfile := settingValue(profileSection, "source_profile")
+	if sourceProfile == "" || !itemMatchesProfile(itemFields, sourceProfile) {
+		return nil, false
+	}
+
+	fields := make(map[sdk.FieldName]string, len(itemFields)+len(roleSettingsMapping))
+	for fieldName, value := range itemFields {
+		fields[fieldName] = value
+	}
+	for key, fieldName := range roleSettingsMapping {
+		if value := settingValue(profileSection, key); value != "" {
+			fields[fieldName] = value
+		}
+	}
+
+	return fields, true
+}
+
+// loadProfileFiles loads the local config and credentials file. Files that don't exist or can't be parsed are nil.
+func loadProfileFiles(in sdk.ProvisionInput) (configFile *ini.File, credentialsFile *ini.File) {
+	configPath := in.Getenv("AWS_CONFIG_FILE")
+	if configPath == "" {
+		configPath = "~/.aws/config"
+	}
+	if file, err := ini.Load(in.ResolvePath(configPath)); err == nil {
+		configFile = file
+	}
+
+	credentialsPath := in.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsPath == "" {
+		credentialsPath = "~/.aws/credentials"
+	}
+	if file, err := ini.Load(in.ResolvePath(credentialsPath)); err == nil {
+		credentialsFile = file
+	}
+
+	return configFile, credentialsFile
+}
+
+// profileExists reports whether a profile with the specified name exists in the local config or credentials file.
+func profileExists(configFile *ini.File, credentialsFile *ini.File, profile string) bool {
+	if getConfigSectionByProfile(configFile, profile) != nil {
+		return true
+	}
+
+	if credentialsFile != nil {
+		if _, err := credentialsFile.GetSection(profile); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"time"
 
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/provision"
@@ -11,6 +12,8 @@ import (
 type awsProvisioner struct {
 	stsProvisioner    STSProvisioner
 	envVarProvisioner provision.EnvVarProvisioner
+	newSTSClient      STSClientFactory
+	cliVersion        cliVersionFunc
 }
 
 func AWSProvisioner() sdk.Provisioner {
@@ -22,12 +25,57 @@ func AWSProvisioner() sdk.Provisioner {
 }
 
 func (p awsProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	itemFields, err := applyProfile(in, out)
+	if err != nil {
+		out.AddError(err)
+		return
+	}
+	in.ItemFields = itemFields
+
+	if isCredentialProcessMode(out.CommandLine) {
+		if err := checkCredentialProcessSupport(ctx, out.CommandLine[0], p.cliVersion); err != nil {
+			out.AddError(err)
+			return
+		}
+		defer func() {
+			if len(out.Diagnostics.Errors) == 0 {
+				out.CommandLine = toCredentialProcessCommand(out.CommandLine)
+			}
+		}()
+	}
+
 	totp, hasTotp := in.ItemFields[fieldname.OneTimePassword]
 	mfaSerial, hasMFASerial := in.ItemFields[fieldname.MFASerial]
 
-	if hasTotp && hasMFASerial {
+	var duration time.Duration
+	if value, ok := in.ItemFields[fieldname.Duration]; ok && value != "" {
+		duration, err = parseDuration(value)
+		if err != nil {
+			out.AddError(err)
+			return
+		}
+	}
+
+	if roleARN, ok := in.ItemFields[fieldname.RoleARN]; ok && roleARN != "" {
+		assumeRoleProvisioner := AssumeRoleProvisioner{
+			RoleARNs:    parseRoleARNs(roleARN),
+			ExternalID:  in.ItemFields[fieldname.ExternalID],
+			SessionName: in.ItemFields[fieldname.SessionName],
+			Duration:    duration,
+			NewClient:   p.newSTSClient,
+		}
+
+		if hasTotp && hasMFASerial {
+			assumeRoleProvisioner.MFASerial = mfaSerial
+			assumeRoleProvisioner.TOTPCode = totp
+		}
+
+		assumeRoleProvisioner.Provision(ctx, in, out)
+	} else if hasTotp && hasMFASerial {
 		p.stsProvisioner.MFASerial = mfaSerial
 		p.stsProvisioner.TOTPCode = totp
+		p.stsProvisioner.Duration = duration
+		p.stsProvisioner.NewClient = p.newSTSClient
 		p.stsProvisioner.Provision(ctx, in, out)
 	} else {
 		p.envVarProvisioner.Provision(ctx, in, out)
@@ -39,5 +87,5 @@ func (p awsProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput
 }
 
 func (p awsProvisioner) Description() string {
-	return p.envVarProvisioner.Description()
+	return "Provision environment variables with the access key, or with temporary credentials from STS if the item has an MFA serial or role ARN: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_DEFAULT_REGION"
 }
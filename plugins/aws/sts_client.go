@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// STSClient is the subset of the STS API used by the provisioners. It allows tests to replace the client that
+// talks to AWS.
+type STSClient interface {
+	GetSessionToken(ctx context.Context, params *sts.GetSessionTokenInput, optFns ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error)
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+}
+
+// STSClientFactory creates an STS client for the specified config, which holds the region and credentials to use.
+type STSClientFactory func(config aws.Config) STSClient
+
+// DefaultSTSClient creates a client that talks to the AWS STS API.
+func DefaultSTSClient(config aws.Config) STSClient {
+	return sts.NewFromConfig(config)
+}
+
+// newClient returns the client created by the factory, falling back to DefaultSTSClient if it's not set.
+func (f STSClientFactory) newClient(config aws.Config) STSClient {
+	if f == nil {
+		return DefaultSTSClient(config)
+	}
+	return f(config)
+}
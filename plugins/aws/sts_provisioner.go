@@ -2,11 +2,14 @@ package aws
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/otp"
 	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -14,43 +17,87 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts/types"
 )
 
+const (
+	defaultSessionDuration = 15 * time.Minute // minimum expiration time
+	stsCacheKeyBase        = "sts"
+
+	// defaultRefreshThreshold is the minimum remaining lifetime of cached credentials to still use them. This
+	// prevents long-running commands from failing halfway because the credentials expire.
+	defaultRefreshThreshold = 5 * time.Minute
+)
+
 type STSProvisioner struct {
+	// The current MFA code, or the TOTP setup to generate it from: an otpauth:// URI or base32 encoded secret.
 	TOTPCode  string
 	MFASerial string
+
+	// (Optional) Influences how the MFA code is generated if TOTPCode is a TOTP setup.
+	OTPOptions []otp.Option
+
+	// (Optional) How long the session credentials should be valid. Defaults to 15 minutes.
+	Duration time.Duration
+
+	// (Optional) Cached credentials with a shorter remaining lifetime than this get refreshed. Defaults to 5 minutes.
+	RefreshThreshold time.Duration
+
+	// (Optional) Creates the client used to call STS. Defaults to DefaultSTSClient.
+	NewClient STSClientFactory
+}
+
+// cacheKey keys the cache by the access key, the MFA serial, the region and the duration, so that rotating the access
+// key or changing any of the settings doesn't reuse credentials that were obtained for another identity or with other
+// settings. Session credentials aren't tied to a role: items with a role ARN get cached by AssumeRoleProvisioner.
+func (p STSProvisioner) cacheKey(accessKeyID string, region string) string {
+	params := []string{
+		accessKeyID,
+		p.MFASerial,
+		region,
+		p.Duration.String(),
+	}
+	hash := sha256.Sum256([]byte(strings.Join(params, "\n")))
+	return fmt.Sprintf("%s:%s", stsCacheKeyBase, hex.EncodeToString(hash[:]))
 }
 
 func (p STSProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
-	var cached types.Credentials
-	if ok := in.Cache.Get("sts", &cached); ok {
-		out.AddEnvVar("AWS_ACCESS_KEY_ID", *cached.AccessKeyId)
-		out.AddEnvVar("AWS_SECRET_ACCESS_KEY", *cached.SecretAccessKey)
-		out.AddEnvVar("AWS_SESSION_TOKEN", *cached.SessionToken)
+	region, err := getRegion(in)
+	if err != nil {
+		out.AddError(err)
+		return
+	}
 
-		if region, ok := in.ItemFields[fieldname.DefaultRegion]; ok {
-			out.AddEnvVar("AWS_DEFAULT_REGION", region)
-		}
+	// Clean up the entry from before the cache was keyed by the access key and settings
+	if _, ok := in.Cache[stsCacheKeyBase]; ok {
+		out.Cache.Remove(stsCacheKeyBase)
+	}
 
+	cacheKey := p.cacheKey(in.ItemFields[fieldname.AccessKeyID], region)
+	if cached, ok := getCachedCredentials(in.Cache, cacheKey, p.RefreshThreshold); ok {
+		addSTSCredentials(out, cached)
+		out.AddEnvVar("AWS_DEFAULT_REGION", region)
 		return
 	}
 
+	duration := p.Duration
+	if duration == 0 {
+		duration = defaultSessionDuration
+	}
+
 	config := aws.NewConfig()
 	config.Credentials = credentials.NewStaticCredentialsProvider(in.ItemFields[fieldname.AccessKeyID], in.ItemFields[fieldname.SecretAccessKey], "")
+	config.Region = region
 
-	region, ok := in.ItemFields[fieldname.DefaultRegion]
-	if !ok {
-		region = os.Getenv("AWS_DEFAULT_REGION")
-	}
-	if len(region) == 0 {
-		out.AddError(errors.New("region is required for the AWS Shell Plugin MFA workflow: set 'default region' in 1Password or set the 'AWS_DEFAULT_REGION' environment variable yourself"))
+	// The code is only generated when it's needed, since generating it can mean waiting for the next one
+	code, err := otp.Generate(ctx, p.TOTPCode, p.OTPOptions...)
+	if err != nil {
+		out.AddError(fmt.Errorf("generating MFA code: %w", err))
 		return
 	}
-	config.Region = region
 
-	stsProvider := sts.NewFromConfig(*config)
+	stsProvider := p.NewClient.newClient(*config)
 	input := &sts.GetSessionTokenInput{
-		DurationSeconds: aws.Int32(900), // minimum expiration time - 15 minutes
+		DurationSeconds: aws.Int32(int32(duration.Seconds())),
 		SerialNumber:    aws.String(p.MFASerial),
-		TokenCode:       aws.String(p.TOTPCode),
+		TokenCode:       aws.String(code),
 	}
 
 	result, err := stsProvider.GetSessionToken(ctx, input)
@@ -59,12 +106,10 @@ func (p STSProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, ou
 		return
 	}
 
-	out.AddEnvVar("AWS_ACCESS_KEY_ID", *result.Credentials.AccessKeyId)
-	out.AddEnvVar("AWS_SECRET_ACCESS_KEY", *result.Credentials.SecretAccessKey)
-	out.AddEnvVar("AWS_SESSION_TOKEN", *result.Credentials.SessionToken)
+	addSTSCredentials(out, *result.Credentials)
 	out.AddEnvVar("AWS_DEFAULT_REGION", region)
 
-	err = out.Cache.Put("sts", *result.Credentials, *result.Credentials.Expiration)
+	err = out.Cache.Put(cacheKey, *result.Credentials, *result.Credentials.Expiration)
 	if err != nil {
 		out.AddError(fmt.Errorf("failed to serialize aws sts credentials: %w", err))
 	}
@@ -77,3 +122,22 @@ func (p STSProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput
 func (p STSProvisioner) Description() string {
 	return "Provision environment variables with temporary STS credentials AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN"
 }
+
+// getCachedCredentials returns the cached STS credentials at the specified key, unless they expire within
+// the refresh threshold.
+func getCachedCredentials(cache sdk.CacheState, key string, refreshThreshold time.Duration) (types.Credentials, bool) {
+	if refreshThreshold == 0 {
+		refreshThreshold = defaultRefreshThreshold
+	}
+
+	var cached types.Credentials
+	if ok := cache.Get(key, &cached); !ok {
+		return cached, false
+	}
+
+	if cached.Expiration != nil && time.Until(*cached.Expiration) < refreshThreshold {
+		return cached, false
+	}
+
+	return cached, true
+}
@@ -1,12 +1,25 @@
 package aws
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
 	"gopkg.in/ini.v1"
 )
 
 func getConfigSectionByProfile(configFile *ini.File, profileName string) *ini.Section {
+	if configFile == nil {
+		return nil
+	}
+
 	for _, section := range configFile.Sections() {
 		if profileName == "default" && section.Name() == "default" {
 			return section
@@ -20,3 +33,98 @@ func getConfigSectionByProfile(configFile *ini.File, profileName string) *ini.Se
 
 	return nil
 }
+
+// listProfiles returns the names of all profiles defined in the credentials and config file, in order of appearance.
+func listProfiles(credentialsFile *ini.File, configFile *ini.File) []string {
+	var profiles []string
+	seen := make(map[string]bool)
+	add := func(profileName string) {
+		if !seen[profileName] {
+			seen[profileName] = true
+			profiles = append(profiles, profileName)
+		}
+	}
+
+	if credentialsFile != nil {
+		for _, section := range credentialsFile.Sections() {
+			if section.Name() != ini.DefaultSection {
+				add(section.Name())
+			}
+		}
+	}
+
+	if configFile != nil {
+		for _, section := range configFile.Sections() {
+			if section.Name() == "default" {
+				add(section.Name())
+			} else if strings.HasPrefix(section.Name(), "profile ") {
+				add(strings.TrimPrefix(section.Name(), "profile "))
+			}
+		}
+	}
+
+	return profiles
+}
+
+func hasSetting(section *ini.Section, key string) bool {
+	return settingValue(section, key) != ""
+}
+
+func settingValue(section *ini.Section, key string) string {
+	if section == nil || !section.HasKey(key) {
+		return ""
+	}
+	return section.Key(key).Value()
+}
+
+// loadINIFile loads an AWS config or credentials file. Returns nil if the file doesn't exist.
+func loadINIFile(in sdk.ImportInput, path string) (*ini.File, error) {
+	contents, err := os.ReadFile(in.ResolvePath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return importer.FileContents(contents).ToINI()
+}
+
+// getRegion returns the region set on the item, falling back to the AWS_DEFAULT_REGION environment variable.
+// STS requires a region to be set.
+func getRegion(in sdk.ProvisionInput) (string, error) {
+	region, ok := in.ItemFields[fieldname.DefaultRegion]
+	if !ok {
+		region = in.Getenv("AWS_DEFAULT_REGION")
+	}
+	if len(region) == 0 {
+		return "", errors.New("region is required to get temporary credentials from AWS STS: set 'default region' in 1Password or set the 'AWS_DEFAULT_REGION' environment variable yourself")
+	}
+	return region, nil
+}
+
+// addSTSCredentials provisions temporary STS credentials as environment variables. The expiration is included
+// as well, so the AWS CLI and SDKs know when the credentials need to be refreshed.
+func addSTSCredentials(out *sdk.ProvisionOutput, creds types.Credentials) {
+	out.AddEnvVar("AWS_ACCESS_KEY_ID", *creds.AccessKeyId)
+	out.AddEnvVar("AWS_SECRET_ACCESS_KEY", *creds.SecretAccessKey)
+	out.AddEnvVar("AWS_SESSION_TOKEN", *creds.SessionToken)
+
+	if creds.Expiration != nil {
+		out.AddEnvVar("AWS_CREDENTIAL_EXPIRATION", creds.Expiration.UTC().Format(time.RFC3339))
+	}
+}
+
+// parseDuration parses a duration set on the item, which can either be a number of seconds (e.g. "3600")
+// or a Go duration string (e.g. "1h" or "45m").
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s': use a number of seconds or a duration such as '1h'", value)
+	}
+
+	return duration, nil
+}
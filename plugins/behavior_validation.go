@@ -0,0 +1,288 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/schema"
+)
+
+// Provisioners that exchange the credential over the network get this long before they're considered stuck.
+const behaviorValidationTimeout = 10 * time.Second
+
+// ValidateBehavior runs the default provisioner and importer of each credential type of the plugin against an item
+// with example values, generated from the value compositions of the fields. It checks whether all required fields get
+// provisioned, whether no secrets end up on the command line and whether the importer finds the provisioned
+// credential again. Returns a report for each credential type.
+func ValidateBehavior(p schema.Plugin) []schema.ValidationReport {
+	var reports []schema.ValidationReport
+	for _, cred := range p.Credentials {
+		report := schema.ValidationReport{
+			Heading: fmt.Sprintf("Behavior: %s", cred.Name),
+			Checks:  []schema.ValidationCheck{},
+		}
+
+		root, err := os.MkdirTemp("", "shell-plugins-validation-")
+		if err != nil {
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "behavior-temp-dir",
+				Description: fmt.Sprintf("Can create a temporary directory to run the provisioner in: %s", err),
+				Assertion:   false,
+				Severity:    schema.ValidationSeverityError,
+			})
+			reports = append(reports, report)
+			continue
+		}
+
+		validateCredentialBehavior(p, cred, root, &report)
+		os.RemoveAll(root)
+
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func validateCredentialBehavior(p schema.Plugin, cred schema.CredentialType, root string, report *schema.ValidationReport) {
+	itemFields := exampleItemFields(cred)
+
+	errs := cred.ValidateFields(itemFields)
+	report.AddCheck(schema.ValidationCheck{
+		ID:          "behavior-example-values",
+		Description: "Example values generated from the value compositions are valid" + joinErrors(errs),
+		Assertion:   len(errs) == 0,
+		Severity:    schema.ValidationSeverityError,
+	})
+
+	if cred.DefaultProvisioner == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), behaviorValidationTimeout)
+	defer cancel()
+
+	in := sdk.ProvisionInput{
+		HomeDir:    filepath.Join(root, "~"),
+		TempDir:    filepath.Join(root, "tmp"),
+		Cache:      make(sdk.CacheState),
+		ItemFields: itemFields,
+		// Run against an empty environment, so the results don't depend on the shell validation gets run from
+		Environment: map[string]string{},
+	}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		CommandLine: executableCommand(p, cred),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+	cred.DefaultProvisioner.Provision(ctx, in, &out)
+
+	var provisionErrs []error
+	for _, err := range out.Diagnostics.Errors {
+		provisionErrs = append(provisionErrs, fmt.Errorf("%s", err.Message))
+	}
+	report.AddCheck(schema.ValidationCheck{
+		ID:          "behavior-provision",
+		Description: "Default provisioner provisions the example item without errors" + joinErrors(provisionErrs),
+		Assertion:   len(provisionErrs) == 0,
+		Severity:    schema.ValidationSeverityError,
+	})
+	if len(provisionErrs) > 0 {
+		return
+	}
+
+	for _, field := range cred.Fields {
+		value := itemFields[field.Name]
+		if value == "" {
+			continue
+		}
+
+		if !field.Optional {
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "behavior-field-provisioned",
+				Subject:     field.Name.String(),
+				Description: fmt.Sprintf("Field '%s' gets provisioned as an environment variable, file or argument", field.Name),
+				Assertion:   isProvisioned(value, out),
+				Severity:    schema.ValidationSeverityError,
+			})
+		}
+
+		if field.Secret {
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "behavior-secret-args",
+				Subject:     field.Name.String(),
+				Description: fmt.Sprintf("Secret field '%s' does not get provisioned on the command line", field.Name),
+				Assertion:   !containsValue(out.CommandLine, value),
+				Severity:    schema.ValidationSeverityError,
+			})
+		}
+	}
+
+	// Importers only look at environment variables and well-known paths, so they can't find credentials that are
+	// provisioned as files passed on the command line.
+	if cred.Importer == nil || passesFilesAsArgs(out) {
+		return
+	}
+
+	found, err := importsProvisionedItem(cred, root, itemFields, out)
+	if err != nil {
+		report.AddCheck(schema.ValidationCheck{
+			ID:          "behavior-importer-run",
+			Description: fmt.Sprintf("Importer can be run against the provisioned credential: %s", err),
+			Assertion:   false,
+			Severity:    schema.ValidationSeverityWarning,
+		})
+		return
+	}
+	report.AddCheck(schema.ValidationCheck{
+		ID:          "behavior-import-round-trip",
+		Description: "Importer finds the provisioned credential",
+		Assertion:   found,
+		Severity:    schema.ValidationSeverityWarning,
+	})
+}
+
+// exampleItemFields generates example values for all required fields. Fields for which no example value can be
+// derived get a recognizable placeholder value.
+func exampleItemFields(cred schema.CredentialType) map[sdk.FieldName]string {
+	// Credential types whose secrets are all optional, because they can be provisioned in more than one way, get an
+	// example value for their first secret field, so that the item holds a credential to provision.
+	var fallbackSecret sdk.FieldName
+	if !hasRequiredSecret(cred) {
+		for _, field := range cred.Fields {
+			if field.Secret {
+				fallbackSecret = field.Name
+				break
+			}
+		}
+	}
+
+	fields := make(map[sdk.FieldName]string)
+	for _, field := range cred.Fields {
+		if field.Optional && field.Name != fallbackSecret {
+			continue
+		}
+
+		if value := plugintest.ExampleFieldValue(field); value != "" {
+			fields[field.Name] = value
+		} else {
+			fields[field.Name] = "example" + strings.ReplaceAll(field.Name.String(), " ", "")
+		}
+	}
+	return fields
+}
+
+func hasRequiredSecret(cred schema.CredentialType) bool {
+	for _, field := range cred.Fields {
+		if field.Secret && !field.Optional {
+			return true
+		}
+	}
+	return false
+}
+
+// executableCommand returns the command of the first executable that uses the credential, to pass to the
+// provisioner as the command line.
+func executableCommand(p schema.Plugin, cred schema.CredentialType) []string {
+	for _, exe := range p.Executables {
+		for _, usage := range exe.Uses {
+			if usage.Name == cred.Name && (usage.Plugin == "" || usage.Plugin == p.Name) && len(exe.Runs) > 0 {
+				return exe.Runs
+			}
+		}
+	}
+	return []string{p.Name}
+}
+
+// isProvisioned reports whether the value is part of any environment variable, file or argument. Values can be part
+// of a larger value, like a connection string or config file.
+func isProvisioned(value string, out sdk.ProvisionOutput) bool {
+	for _, envVarValue := range out.Environment {
+		if strings.Contains(envVarValue, value) {
+			return true
+		}
+	}
+	for _, file := range out.Files {
+		if strings.Contains(string(file.Contents), value) {
+			return true
+		}
+	}
+	return containsValue(out.CommandLine, value)
+}
+
+// passesFilesAsArgs reports whether the credential is provisioned only as files that get passed on the command line.
+func passesFilesAsArgs(out sdk.ProvisionOutput) bool {
+	if len(out.Environment) > 0 || len(out.Files) == 0 {
+		return false
+	}
+	for path := range out.Files {
+		if !containsValue(out.CommandLine, path) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(args []string, value string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// importsProvisionedItem writes the provisioned files to disk, runs the importer with only the provisioned environment
+// variables set and checks whether any of the candidates the importer returns has all values of the item.
+func importsProvisionedItem(cred schema.CredentialType, root string, itemFields map[sdk.FieldName]string, out sdk.ProvisionOutput) (bool, error) {
+	for path, file := range out.Files {
+		if !strings.HasPrefix(path, root) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(path, file.Contents, 0600); err != nil {
+			return false, err
+		}
+	}
+
+	importOut := sdk.ImportOutput{}
+	cred.Importer(context.Background(), sdk.ImportInput{
+		HomeDir:     filepath.Join(root, "~"),
+		RootDir:     root,
+		Environment: out.Environment,
+	}, &importOut)
+
+	for _, candidate := range importOut.AllCandidates() {
+		matches := true
+		for name, value := range itemFields {
+			if candidate.Fields[name] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// joinErrors formats the errors to be appended to a check description.
+func joinErrors(errs []error) string {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return ": " + strings.Join(messages, "; ")
+}
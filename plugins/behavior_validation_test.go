@@ -0,0 +1,94 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/credname"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+)
+
+// argsProvisioner provisions the token as a command-line flag.
+type argsProvisioner struct {
+	sdk.Provisioner
+}
+
+func (p argsProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	out.AddArgs("--token", in.ItemFields[fieldname.Token])
+}
+
+func behaviorTestCredential() schema.CredentialType {
+	envVarMapping := map[string]sdk.FieldName{
+		"FOO_TOKEN": fieldname.Token,
+		"FOO_HOST":  fieldname.Host,
+	}
+
+	return schema.CredentialType{
+		Name: credname.APIToken,
+		Fields: []schema.CredentialField{
+			{
+				Name:   fieldname.Token,
+				Secret: true,
+				Composition: &schema.ValueComposition{
+					Length:  24,
+					Prefix:  "foo_",
+					Charset: schema.Charset{Lowercase: true, Digits: true},
+				},
+			},
+			{
+				Name: fieldname.Host,
+			},
+			{
+				Name:     fieldname.Region,
+				Optional: true,
+			},
+		},
+		DefaultProvisioner: provision.EnvVars(envVarMapping),
+		Importer:           importer.TryEnvVarPair(envVarMapping),
+	}
+}
+
+func TestValidateBehaviorValid(t *testing.T) {
+	cred := behaviorTestCredential()
+	p := testPlugin("foo", "foo", cred)
+
+	assert.Empty(t, failedChecks(ValidateBehavior(p)))
+}
+
+func TestValidateBehaviorErrors(t *testing.T) {
+	cred := behaviorTestCredential()
+	cred.DefaultProvisioner = argsProvisioner{}
+	cred.Importer = importer.TryEnvVarPair(map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token})
+	p := testPlugin("foo", "foo", cred)
+
+	assert.Equal(t, map[string][]string{
+		"Behavior: API Token": {
+			"Secret field 'Token' does not get provisioned on the command line",
+			"Field 'Host' gets provisioned as an environment variable, file or argument",
+			"Importer finds the provisioned credential",
+		},
+	}, failedChecks(ValidateBehavior(p)))
+}
+
+func TestValidateBehaviorProvisionErrors(t *testing.T) {
+	cred := behaviorTestCredential()
+	cred.DefaultProvisioner = provision.EnvVars(
+		map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token},
+		provision.Transform("FOO_TOKEN", func(value string) (string, error) {
+			return "", errors.New("unsupported token")
+		}),
+	)
+	p := testPlugin("foo", "foo", cred)
+
+	assert.Equal(t, map[string][]string{
+		"Behavior: API Token": {
+			"Default provisioner provisions the example item without errors: transforming value for FOO_TOKEN: unsupported token",
+		},
+	}, failedChecks(ValidateBehavior(p)))
+}
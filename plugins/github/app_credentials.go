@@ -0,0 +1,53 @@
+package github
+
+import (
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/credname"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+// AppCredentials authenticates as a GitHub App installation, by exchanging the app's private key for a short-lived
+// installation access token.
+func AppCredentials() schema.CredentialType {
+	return schema.CredentialType{
+		Name:          credname.AppCredentials,
+		DocsURL:       sdk.URL("https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation"),
+		ManagementURL: sdk.URL("https://github.com/settings/apps"),
+		Fields: []schema.CredentialField{
+			{
+				Name:                fieldname.AppID,
+				MarkdownDescription: "The ID of the GitHub App.",
+				Composition: &schema.ValueComposition{
+					Charset: schema.Charset{
+						Digits: true,
+					},
+				},
+			},
+			{
+				Name:                fieldname.PrivateKey,
+				MarkdownDescription: "The PEM-encoded private key of the GitHub App, used to sign the JWT to request installation tokens with.",
+				Secret:              true,
+				Type:                schema.FieldTypePEM,
+			},
+			{
+				Name:                fieldname.InstallationID,
+				MarkdownDescription: "The ID of the installation to request a token for. Can be omitted if the app is installed only once.",
+				Optional:            true,
+				Composition: &schema.ValueComposition{
+					Charset: schema.Charset{
+						Digits: true,
+					},
+				},
+			},
+			{
+				Name:                fieldname.Host,
+				MarkdownDescription: "The GitHub host the app is registered on. Defaults to 'github.com'.",
+				Optional:            true,
+				Type:                schema.FieldTypeHostname,
+				Default:             "github.com",
+			},
+		},
+		DefaultProvisioner: InstallationTokenProvisioner{},
+	}
+}
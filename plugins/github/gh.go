@@ -13,9 +13,15 @@ func GitHubCLI() schema.Executable {
 		Runs:      []string{"gh"},
 		DocsURL:   sdk.URL("https://cli.github.com"),
 		NeedsAuth: needsauth.NotForHelpOrVersion(),
+		// gh can authenticate either with a token or as a GitHub App installation, so only one of them is needed
 		Uses: []schema.CredentialUsage{
 			{
-				Name: credname.PersonalAccessToken,
+				Name:     credname.PersonalAccessToken,
+				Optional: true,
+			},
+			{
+				Name:     credname.AppCredentials,
+				Optional: true,
 			},
 		},
 	}
@@ -0,0 +1,225 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+const (
+	installationTokenCacheKeyBase = "github-installation-token"
+
+	// GitHub rejects JWTs that are valid for longer than 10 minutes. The issue time is backdated to allow for clock drift.
+	jwtLifetime  = 9 * time.Minute
+	jwtClockSkew = time.Minute
+
+	defaultRefreshThreshold = 5 * time.Minute
+)
+
+// InstallationTokenProvisioner provisions an installation access token for a GitHub App as GITHUB_TOKEN. The token
+// is requested using a JWT signed with the app's private key and gets cached until it's about to expire.
+type InstallationTokenProvisioner struct {
+	// (Optional) Cached tokens with a shorter remaining lifetime than this get refreshed. Defaults to 5 minutes.
+	RefreshThreshold time.Duration
+
+	// (Optional) The base URL of the GitHub REST API. Defaults to https://api.github.com, or the GitHub Enterprise
+	// Server API of the host set on the item.
+	APIURL string
+
+	// (Optional) The HTTP client to use to talk to GitHub. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type installation struct {
+	ID int64 `json:"id"`
+}
+
+func (p InstallationTokenProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	appID := in.ItemFields[fieldname.AppID]
+	installationID := in.ItemFields[fieldname.InstallationID]
+	apiURL := p.apiURL(in.ItemFields[fieldname.Host])
+	cacheKey := fmt.Sprintf("%s:%s:%s:%s", installationTokenCacheKeyBase, apiURL, appID, installationID)
+
+	refreshThreshold := p.RefreshThreshold
+	if refreshThreshold == 0 {
+		refreshThreshold = defaultRefreshThreshold
+	}
+
+	var cached installationToken
+	if ok := in.Cache.Get(cacheKey, &cached); ok && time.Until(cached.ExpiresAt) > refreshThreshold {
+		out.AddEnvVar("GITHUB_TOKEN", cached.Token)
+		return
+	}
+
+	jwt, err := signAppJWT(appID, in.ItemFields[fieldname.PrivateKey], time.Now())
+	if err != nil {
+		out.AddError(err)
+		return
+	}
+
+	if installationID == "" {
+		installationID, err = p.findInstallation(ctx, apiURL, jwt)
+		if err != nil {
+			out.AddError(err)
+			return
+		}
+	}
+
+	var token installationToken
+	err = p.request(ctx, http.MethodPost, fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, installationID), jwt, &token)
+	if err != nil {
+		out.AddError(fmt.Errorf("requesting installation token: %w", err))
+		return
+	}
+
+	out.AddEnvVar("GITHUB_TOKEN", token.Token)
+
+	err = out.Cache.Put(cacheKey, token, token.ExpiresAt)
+	if err != nil {
+		out.AddError(fmt.Errorf("failed to serialize github installation token: %w", err))
+	}
+}
+
+func (p InstallationTokenProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	// Nothing to do here: environment variables get wiped automatically when the process exits.
+}
+
+func (p InstallationTokenProvisioner) Description() string {
+	return "Provision environment variable GITHUB_TOKEN with an installation access token for the GitHub App"
+}
+
+func (p InstallationTokenProvisioner) apiURL(host string) string {
+	if p.APIURL != "" {
+		return strings.TrimSuffix(p.APIURL, "/")
+	}
+	if host == "" || host == "github.com" {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", host)
+}
+
+// findInstallation returns the ID of the app's installation, if the app is installed only once.
+func (p InstallationTokenProvisioner) findInstallation(ctx context.Context, apiURL string, jwt string) (string, error) {
+	var installations []installation
+	err := p.request(ctx, http.MethodGet, apiURL+"/app/installations", jwt, &installations)
+	if err != nil {
+		return "", fmt.Errorf("listing app installations: %w", err)
+	}
+
+	switch len(installations) {
+	case 0:
+		return "", errors.New("the GitHub App is not installed on any account")
+	case 1:
+		return fmt.Sprint(installations[0].ID), nil
+	default:
+		return "", fmt.Errorf("the GitHub App is installed on %d accounts: set '%s' in 1Password to select one", len(installations), fieldname.InstallationID)
+	}
+}
+
+func (p InstallationTokenProvisioner) request(ctx context.Context, method string, url string, jwt string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, errResp.Message)
+		}
+		return errors.New(resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// signAppJWT creates the RS256-signed JWT that authenticates as the GitHub App.
+func signAppJWT(appID string, privateKeyPEM string, now time.Time) (string, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing JWT: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses the RSA private key GitHub generates for apps, which is PKCS #1 encoded, but also accepts
+// PKCS #8 encoded keys.
+func parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("private key is not a valid RSA private key")
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA private key")
+	}
+
+	return key, nil
+}
@@ -23,12 +23,16 @@ func PersonalAccessToken() schema.CredentialType {
 				MarkdownDescription: "Token used to authenticate to GitHub.",
 				Secret:              true,
 				Composition: &schema.ValueComposition{
-					Length: 40,
-					Prefix: "ghp_",
+					MinLength: 40,
+					MaxLength: 93,
+					Prefixes:  tokenPrefixes,
+					Pattern:   `gh[opu]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9]{22}_[A-Za-z0-9]{59}`,
+					Checksum:  tokenChecksum{},
 					Charset: schema.Charset{
 						Uppercase: true,
 						Lowercase: true,
 						Digits:    true,
+						Specific:  []rune{'_'},
 					},
 				},
 			},
@@ -36,6 +40,8 @@ func PersonalAccessToken() schema.CredentialType {
 				Name:                fieldname.Host,
 				MarkdownDescription: "The GitHub host to authenticate to. Defaults to 'github.com'.",
 				Optional:            true,
+				Type:                schema.FieldTypeHostname,
+				Default:             "github.com",
 			},
 		},
 		DefaultProvisioner: provision.EnvVars(defaultEnvVarMapping),
@@ -67,6 +73,21 @@ var defaultEnvVarMapping = map[string]sdk.FieldName{
 	"GITHUB_TOKEN": fieldname.Token,
 }
 
+// tokenPrefixes are the prefixes of the GitHub tokens that can be used as a personal access token: classic personal
+// access tokens, OAuth tokens (as created by `gh auth login`), user-to-server tokens and fine-grained personal access
+// tokens. Installation (ghs_) and refresh (ghr_) tokens are left out, since they're short-lived.
+var tokenPrefixes = []string{"ghp_", "gho_", "ghu_", "github_pat_"}
+
+// isPersonalAccessToken reports whether the value has the format of a token that can be used as a personal access token.
+func isPersonalAccessToken(value string) bool {
+	for _, prefix := range tokenPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 type Config struct {
 	Token string `yaml:"oauth_token"`
 }
@@ -80,7 +101,7 @@ func TryGitHubConfigFile() sdk.Importer {
 		}
 
 		for host, values := range config {
-			if strings.HasPrefix(values.Token, "ghp_") {
+			if isPersonalAccessToken(values.Token) {
 				candidate := sdk.ImportCandidate{
 					Fields: map[sdk.FieldName]string{
 						fieldname.Token: values.Token,
@@ -14,6 +14,7 @@ func New() schema.Plugin {
 		},
 		Credentials: []schema.CredentialType{
 			PersonalAccessToken(),
+			AppCredentials(),
 		},
 		Executables: []schema.Executable{
 			GitHubCLI(),
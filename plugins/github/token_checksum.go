@@ -0,0 +1,70 @@
+package github
+
+import (
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// Tokens with a short prefix, like ghp_, consist of 30 random characters followed by a 6 character checksum.
+	tokenRandomLength   = 30
+	tokenChecksumLength = 6
+)
+
+// tokenChecksum validates the checksum at the end of GitHub tokens with a short prefix, like ghp_ and gho_: the CRC32
+// checksum of the random part of the token, base62 encoded. Fine-grained personal access tokens don't have a
+// documented checksum, so those are not checked.
+type tokenChecksum struct{}
+
+func (tokenChecksum) ValidateChecksum(value string) error {
+	random, checksum, ok := splitToken(value)
+	if !ok {
+		return nil
+	}
+
+	if checksum != crc32Base62(random) {
+		return errors.New("the last 6 characters don't match the CRC32 checksum of the token")
+	}
+	return nil
+}
+
+func (tokenChecksum) AddChecksum(value string) string {
+	random, _, ok := splitToken(value)
+	if !ok {
+		return value
+	}
+
+	return value[:len(value)-tokenChecksumLength] + crc32Base62(random)
+}
+
+// splitToken splits a token with a short prefix into its random part and its checksum.
+func splitToken(value string) (random string, checksum string, ok bool) {
+	if strings.HasPrefix(value, "github_pat_") {
+		return "", "", false
+	}
+
+	_, rest, found := strings.Cut(value, "_")
+	if !found || len(rest) != tokenRandomLength+tokenChecksumLength {
+		return "", "", false
+	}
+
+	return rest[:tokenRandomLength], rest[tokenRandomLength:], true
+}
+
+// crc32Base62 returns the base62 encoded CRC32 checksum of the value, left-padded with zeros to 6 characters.
+func crc32Base62(value string) string {
+	n := crc32.ChecksumIEEE([]byte(value))
+
+	encoded := make([]byte, tokenChecksumLength)
+	for i := range encoded {
+		encoded[i] = '0'
+	}
+	for i := len(encoded) - 1; n > 0 && i >= 0; i-- {
+		encoded[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(encoded)
+}
@@ -1,10 +1,6 @@
 package heroku
 
 import (
-	"bufio"
-	"context"
-	"strings"
-
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/importer"
 	"github.com/1Password/shell-plugins/sdk/provision"
@@ -44,34 +40,7 @@ func APIKey() schema.CredentialType {
 
 // TryNetrcFile tries to find Heroku API keys in the ~/.netrc file
 func TryNetrcFile() sdk.Importer {
-	return importer.TryFile("~/.netrc", func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
-		s := bufio.NewScanner(strings.NewReader(string(contents)))
-		var machine, login, password string
-		for s.Scan() {
-			if words := strings.Split(strings.Trim(s.Text(), " "), " "); len(words) >= 2 {
-				switch words[0] {
-				case "machine":
-					if machine != "" {
-						login, password = "", ""
-					}
-					machine = words[1]
-				case "login":
-					login = words[1]
-				case "password":
-					password = words[1]
-				}
-				if login != "" && password != "" && machine != "" {
-					if machine == "api.heroku.com" || machine == "git.heroku.com" {
-						out.AddCandidate(sdk.ImportCandidate{
-							NameHint: importer.SanitizeNameHint(login),
-							Fields: map[sdk.FieldName]string{
-								fieldname.APIKey: password,
-							},
-						})
-					}
-					machine, login, password = "", "", ""
-				}
-			}
-		}
-	})
+	return importer.TryNetrcFile(map[string]sdk.FieldName{
+		importer.NetrcPassword: fieldname.APIKey,
+	}, "api.heroku.com", "git.heroku.com")
 }
@@ -41,6 +41,19 @@ func TestAPIKeyImporter(t *testing.T) {
 				},
 			},
 		},
+		"netrc file with tabs, macros and default entry": {
+			Files: map[string]string{
+				"~/.netrc": plugintest.LoadFixture(t, "netrc-tabs-macdef"),
+			},
+			ExpectedCandidates: []sdk.ImportCandidate{
+				{
+					NameHint: "wendy@appleseed.com",
+					Fields: map[sdk.FieldName]string{
+						fieldname.APIKey: "dh7k7m662pqglxaybr1p0gpg1cu33example",
+					},
+				},
+			},
+		},
 		"netrc file non-Heroku": {
 			Files: map[string]string{
 				"~/.netrc": plugintest.LoadFixture(t, "netrc-non-heroku"),
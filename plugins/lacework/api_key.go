@@ -32,10 +32,11 @@ func APIKey() schema.CredentialType {
 				Name:                fieldname.APIKeyID,
 				MarkdownDescription: "The API key used to authenticate to Lacework.",
 				Composition: &schema.ValueComposition{
+					Pattern: `[A-Z0-9-]+_[A-Z0-9]{32,}`,
 					Charset: schema.Charset{
 						Uppercase: true,
 						Digits:    true,
-						Specific:  []rune{'-'},
+						Specific:  []rune{'-', '_'},
 					},
 				},
 			},
@@ -21,11 +21,15 @@ func DatabaseCredentials() schema.CredentialType {
 				Name:                fieldname.Host,
 				MarkdownDescription: "MySQL host to connect to.",
 				Optional:            true,
+				Type:                schema.FieldTypeHostname,
+				Default:             "localhost",
 			},
 			{
 				Name:                fieldname.Port,
 				MarkdownDescription: "Port used to connect to MySQL.",
 				Optional:            true,
+				Type:                schema.FieldTypePort,
+				Default:             "3306",
 			},
 			{
 				Name:                fieldname.User,
@@ -49,6 +53,7 @@ func DatabaseCredentials() schema.CredentialType {
 			TryMySQLConfigFile("/etc/mysql/my.cnf"),
 			TryMySQLConfigFile("~/.my.cnf"),
 			TryMySQLConfigFile("~/.mylogin.cnf"),
+			importer.TryConnectionURIEnvVar("DATABASE_URL", "mysql"),
 		),
 	}
 }
@@ -79,43 +84,69 @@ func mysqlConfig(in sdk.ProvisionInput) ([]byte, error) {
 	return []byte(content), nil
 }
 
+// TryMySQLConfigFile looks for credentials in a MySQL option file, adding a separate import candidate for
+// each client section. Obfuscated login path files (~/.mylogin.cnf) get decoded first, in which case every
+// login path becomes a candidate with its name as the name hint.
 func TryMySQLConfigFile(path string) sdk.Importer {
 	return importer.TryFile(path, func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+		isLoginPath := isLoginPathFile(contents)
+		if isLoginPath {
+			decoded, err := decodeLoginPathFile(contents)
+			if err != nil {
+				out.AddError(err)
+				return
+			}
+			contents = decoded
+		}
+
 		credentialsFile, err := contents.ToINI()
 		if err != nil {
 			out.AddError(err)
 			return
 		}
 
-		fields := make(map[sdk.FieldName]string)
 		for _, section := range credentialsFile.Sections() {
-			if section.HasKey("user") && section.Key("user").Value() != "" {
-				fields[fieldname.User] = section.Key("user").Value()
+			// In regular option files, only the sections read by the mysql client contain credentials
+			if !isLoginPath && !clientSections[section.Name()] {
+				continue
 			}
 
-			if section.HasKey("password") && section.Key("password").Value() != "" {
-				fields[fieldname.Password] = section.Key("password").Value()
+			fields := make(map[sdk.FieldName]string)
+			for key, fieldName := range configFileMapping {
+				if section.HasKey(key) && section.Key(key).Value() != "" {
+					fields[fieldName] = section.Key(key).Value()
+				}
 			}
 
-			if section.HasKey("database") && section.Key("database").Value() != "" {
-				fields[fieldname.Database] = section.Key("database").Value()
+			if len(fields) == 0 {
+				continue
 			}
 
-			if section.HasKey("host") && section.Key("host").Value() != "" {
-				fields[fieldname.Host] = section.Key("host").Value()
+			candidate := sdk.ImportCandidate{
+				Fields: fields,
 			}
-
-			if section.HasKey("port") && section.Key("port").Value() != "" {
-				fields[fieldname.Port] = section.Key("port").Value()
+			if isLoginPath {
+				candidate.NameHint = importer.SanitizeNameHint(section.Name())
 			}
-		}
 
-		out.AddCandidate(sdk.ImportCandidate{
-			Fields: fields,
-		})
+			out.AddCandidate(candidate)
+		}
 	})
 }
 
+var clientSections = map[string]bool{
+	"client": true,
+	"mysql":  true,
+}
+
+var configFileMapping = map[string]sdk.FieldName{
+	"user":     fieldname.User,
+	"password": fieldname.Password,
+	"database": fieldname.Database,
+	"host":     fieldname.Host,
+	"port":     fieldname.Port,
+}
+
 func configFileEntry(key string, value string) string {
 	return fmt.Sprintf("%s=%s\n", key, value)
 }
@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+)
+
+// The login path file (~/.mylogin.cnf) written by mysql_config_editor is obfuscated: it starts with 4 unused
+// bytes, followed by 20 bytes of key material, followed by the lines of an option file, each encrypted separately
+// with AES-128-ECB and prefixed with the length of the ciphertext as a 4-byte little-endian integer.
+const (
+	loginPathUnusedLength = 4
+	loginPathKeyLength    = 20
+	loginPathHeaderLength = loginPathUnusedLength + loginPathKeyLength
+	loginPathCipherLength = 4
+)
+
+var errInvalidLoginPathFile = errors.New("invalid login path file")
+
+// isLoginPathFile reports whether the contents look like an obfuscated login path file, rather than
+// a plain text option file.
+func isLoginPathFile(contents []byte) bool {
+	if len(contents) < loginPathHeaderLength {
+		return false
+	}
+	return bytes.Equal(contents[:loginPathUnusedLength], make([]byte, loginPathUnusedLength))
+}
+
+// decodeLoginPathFile decrypts the contents of a login path file to a plain text option file.
+func decodeLoginPathFile(contents []byte) ([]byte, error) {
+	if !isLoginPathFile(contents) {
+		return nil, errInvalidLoginPathFile
+	}
+
+	// The AES key is derived by folding the 20 bytes of key material into 16 bytes.
+	key := make([]byte, aes.BlockSize)
+	for i, b := range contents[loginPathUnusedLength:loginPathHeaderLength] {
+		key[i%aes.BlockSize] ^= b
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var plaintext bytes.Buffer
+	rest := contents[loginPathHeaderLength:]
+	for len(rest) > 0 {
+		if len(rest) < loginPathCipherLength {
+			return nil, errInvalidLoginPathFile
+		}
+
+		cipherLength := int(binary.LittleEndian.Uint32(rest[:loginPathCipherLength]))
+		rest = rest[loginPathCipherLength:]
+		if cipherLength == 0 || cipherLength%aes.BlockSize != 0 || cipherLength > len(rest) {
+			return nil, errInvalidLoginPathFile
+		}
+
+		line := make([]byte, cipherLength)
+		for i := 0; i < cipherLength; i += aes.BlockSize {
+			block.Decrypt(line[i:i+aes.BlockSize], rest[i:i+aes.BlockSize])
+		}
+		rest = rest[cipherLength:]
+
+		// Strip the PKCS#7 padding
+		padding := int(line[len(line)-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errInvalidLoginPathFile
+		}
+		plaintext.Write(line[:len(line)-padding])
+	}
+
+	return plaintext.Bytes(), nil
+}
@@ -33,6 +33,8 @@ func APIToken() schema.CredentialType {
 			{
 				Name:                fieldname.OrgURL,
 				MarkdownDescription: "URL of the Okta organization to authenticate to.",
+				Type:                schema.FieldTypeURL,
+				Placeholder:         "https://example.okta.com",
 			},
 		},
 		DefaultProvisioner: provision.EnvVars(defaultEnvVarMapping),
@@ -3,7 +3,6 @@ package postgresql
 import (
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/importer"
-	"github.com/1Password/shell-plugins/sdk/provision"
 	"github.com/1Password/shell-plugins/sdk/schema"
 	"github.com/1Password/shell-plugins/sdk/schema/credname"
 	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
@@ -22,6 +21,8 @@ func DatabaseCredentials() schema.CredentialType {
 				Name:                fieldname.Port,
 				MarkdownDescription: "Port used to connect to Postgres.",
 				Optional:            true,
+				Type:                schema.FieldTypePort,
+				Default:             "5432",
 			},
 			{
 				Name:                fieldname.User,
@@ -38,8 +39,13 @@ func DatabaseCredentials() schema.CredentialType {
 				Optional:            true,
 			},
 		},
-		DefaultProvisioner: provision.EnvVars(defaultEnvVarMapping),
-		Importer:           importer.TryEnvVarPair(defaultEnvVarMapping),
+		DefaultProvisioner: PasswordFile(),
+		Importer: importer.TryAll(
+			importer.TryEnvVarPair(defaultEnvVarMapping),
+			importer.TryConnectionURIEnvVar("DATABASE_URL", "postgres", "postgresql"),
+			TryPgpassFile(),
+			TryServiceFile(),
+		),
 	}
 }
 
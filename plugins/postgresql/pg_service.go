@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"gopkg.in/ini.v1"
+)
+
+// TryServiceFile looks for credentials in the ~/.pg_service.conf file, or the file set in PGSERVICEFILE,
+// adding a separate import candidate for each service that has a user or password set.
+func TryServiceFile() sdk.Importer {
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		path := "~/.pg_service.conf"
+		if customPath := in.Getenv("PGSERVICEFILE"); customPath != "" {
+			path = customPath
+		}
+
+		importer.TryFile(path, func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+			serviceFile, err := contents.ToINI()
+			if err != nil {
+				out.AddError(err)
+				return
+			}
+
+			for _, section := range serviceFile.Sections() {
+				if section.Name() == ini.DefaultSection {
+					continue
+				}
+
+				fields := make(map[sdk.FieldName]string)
+				for key, fieldName := range serviceFileMapping {
+					if section.HasKey(key) && section.Key(key).Value() != "" {
+						fields[fieldName] = section.Key(key).Value()
+					}
+				}
+
+				if fields[fieldname.User] == "" && fields[fieldname.Password] == "" {
+					continue
+				}
+
+				out.AddCandidate(sdk.ImportCandidate{
+					Fields:   fields,
+					NameHint: importer.SanitizeNameHint(section.Name()),
+				})
+			}
+		})(ctx, in, out)
+	}
+}
+
+var serviceFileMapping = map[string]sdk.FieldName{
+	"host":     fieldname.Host,
+	"port":     fieldname.Port,
+	"user":     fieldname.User,
+	"password": fieldname.Password,
+	"dbname":   fieldname.Database,
+}
@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+// PasswordFile provisions the password as a temporary password file, pointed to by PGPASSFILE, and the
+// other fields as environment variables. This prevents the password from showing up in the environment
+// of the psql process, which is what libpq recommends over using PGPASSWORD. Items without a password
+// only get the environment variables provisioned.
+func PasswordFile() sdk.Provisioner {
+	return provision.All(
+		provision.EnvVars(map[string]sdk.FieldName{
+			"PGHOST":     fieldname.Host,
+			"PGPORT":     fieldname.Port,
+			"PGUSER":     fieldname.User,
+			"PGDATABASE": fieldname.Database,
+		}),
+		pgpassFileProvisioner{
+			Provisioner: provision.TempFile(pgpassContents, provision.Filename("pgpass"), provision.SetPathAsEnvVar("PGPASSFILE")),
+		},
+	)
+}
+
+// pgpassFileProvisioner only writes the password file if the item has a password, so that servers that don't
+// require one, e.g. with trust authentication, can still be connected to.
+type pgpassFileProvisioner struct {
+	sdk.Provisioner
+}
+
+func (p pgpassFileProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	if in.ItemFields[fieldname.Password] == "" {
+		return
+	}
+	p.Provisioner.Provision(ctx, in, out)
+}
+
+// pgpassContents creates a password file with a single entry, using wildcards for the fields that
+// are not set on the item.
+func pgpassContents(in sdk.ProvisionInput) ([]byte, error) {
+	password, ok := in.ItemFields[fieldname.Password]
+	if !ok {
+		return nil, fmt.Errorf("no value present in the item for field '%s'", fieldname.Password)
+	}
+
+	entry := []string{
+		pgpassValue(in.ItemFields[fieldname.Host]),
+		pgpassValue(in.ItemFields[fieldname.Port]),
+		pgpassValue(in.ItemFields[fieldname.Database]),
+		pgpassValue(in.ItemFields[fieldname.User]),
+		pgpassEscape(password),
+	}
+
+	return []byte(strings.Join(entry, ":") + "\n"), nil
+}
+
+func pgpassValue(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return pgpassEscape(value)
+}
+
+func pgpassEscape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, ":", `\:`)
+}
+
+// TryPgpassFile looks for credentials in the ~/.pgpass file, or the file set in PGPASSFILE,
+// adding a separate import candidate for each entry.
+func TryPgpassFile() sdk.Importer {
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		path := "~/.pgpass"
+		if customPath := in.Getenv("PGPASSFILE"); customPath != "" {
+			path = customPath
+		}
+
+		importer.TryFile(path, func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+			s := bufio.NewScanner(strings.NewReader(contents.ToString()))
+			for s.Scan() {
+				line := strings.TrimSpace(s.Text())
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+
+				entry := splitPgpassLine(line)
+				if len(entry) != 5 || entry[4] == "" {
+					continue
+				}
+
+				fields := map[sdk.FieldName]string{
+					fieldname.Password: entry[4],
+				}
+				for i, fieldName := range []sdk.FieldName{fieldname.Host, fieldname.Port, fieldname.Database, fieldname.User} {
+					if entry[i] != "" && entry[i] != "*" {
+						fields[fieldName] = entry[i]
+					}
+				}
+
+				out.AddCandidate(sdk.ImportCandidate{
+					Fields:   fields,
+					NameHint: importer.SanitizeNameHint(pgpassNameHint(fields)),
+				})
+			}
+		})(ctx, in, out)
+	}
+}
+
+// splitPgpassLine splits a line of a password file on every colon that's not escaped with a backslash,
+// unescaping the values in the process.
+func splitPgpassLine(line string) []string {
+	var entry []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			entry = append(entry, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(entry, current.String())
+}
+
+func pgpassNameHint(fields map[sdk.FieldName]string) string {
+	nameHint := fields[fieldname.User]
+	if host := fields[fieldname.Host]; host != "" {
+		if nameHint != "" {
+			nameHint += "@"
+		}
+		nameHint += host
+	}
+	return nameHint
+}
@@ -17,3 +17,14 @@ func TestValidatePlugins(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRegistry(t *testing.T) {
+	for _, report := range ValidateRegistry(registry) {
+		for _, c := range report.Checks {
+			if !c.Assertion && c.Severity == schema.ValidationSeverityError {
+				t.Logf("%s: %s", report.Heading, c.Description)
+				t.Fail()
+			}
+		}
+	}
+}
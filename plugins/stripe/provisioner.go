@@ -0,0 +1,155 @@
+package stripe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+const (
+	liveFlag        = "--live"
+	projectNameFlag = "--project-name"
+)
+
+type stripeProvisioner struct {
+	envVarProvisioner provision.EnvVarProvisioner
+}
+
+// StripeProvisioner provisions the key as STRIPE_API_KEY, after checking that the key matches the mode (--live)
+// and project (--project-name) the Stripe CLI is invoked with. A live mode key is only provisioned if --live is
+// passed, so that live data is never changed by accident, and a test mode key can't be used with --live. If the item
+// is for a specific project and the command doesn't select one, the project of the item is selected.
+func StripeProvisioner() sdk.Provisioner {
+	return stripeProvisioner{
+		envVarProvisioner: provision.EnvVarProvisioner{
+			Schema: defaultEnvVarMapping,
+		},
+	}
+}
+
+func (p stripeProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	keyMode, err := modeOf(in.ItemFields)
+	if err != nil {
+		out.AddError(err)
+		return
+	}
+
+	flags := parseFlags(out.CommandLine)
+	requestedMode := ModeTest
+	if hasFlag(flags, liveFlag) {
+		requestedMode = ModeLive
+	}
+
+	switch {
+	case keyMode == ModeLive && requestedMode == ModeTest:
+		out.AddError(fmt.Errorf("the 1Password item contains a live mode key, but the command doesn't pass %s: pass %s to operate on live data, or select a test mode item", liveFlag, liveFlag))
+		return
+	case keyMode == ModeTest && requestedMode == ModeLive:
+		out.AddError(fmt.Errorf("the 1Password item contains a test mode key, but the command runs in live mode: select a different item or change the '%s' field", fieldname.Mode))
+		return
+	}
+
+	itemProject := in.ItemFields[fieldname.Project]
+	if project, ok := flagValue(flags, projectNameFlag, "-p"); ok {
+		if itemProject != "" && itemProject != project {
+			out.AddError(fmt.Errorf("the 1Password item is configured for Stripe project '%s', but the command uses project '%s': select a different item or change the '%s' field", itemProject, project, fieldname.Project))
+			return
+		}
+	} else if itemProject != "" && len(out.CommandLine) > 0 {
+		// Select the project right after the executable, so it can't end up after "--"
+		commandLine := append([]string{out.CommandLine[0], projectNameFlag, itemProject}, out.CommandLine[1:]...)
+		out.CommandLine = commandLine
+	}
+
+	p.envVarProvisioner.Provision(ctx, in, out)
+}
+
+func (p stripeProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	// Nothing to do here: environment variables get wiped automatically when the process exits.
+}
+
+func (p stripeProvisioner) Description() string {
+	return p.envVarProvisioner.Description()
+}
+
+// modeOf returns the mode of the key in the item: the Mode field if it's set, or else the mode included in the
+// key itself (e.g. sk_live_ or rk_test_). Returns an empty string if the mode is unknown.
+func modeOf(itemFields map[sdk.FieldName]string) (string, error) {
+	if mode := strings.ToLower(strings.TrimSpace(itemFields[fieldname.Mode])); mode != "" {
+		if mode != ModeLive && mode != ModeTest {
+			return "", fmt.Errorf("invalid mode '%s': the '%s' field should be either '%s' or '%s'", itemFields[fieldname.Mode], fieldname.Mode, ModeLive, ModeTest)
+		}
+		return mode, nil
+	}
+
+	key := itemFields[fieldname.Key]
+	for _, prefix := range keyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return strings.Split(prefix, "_")[1], nil
+		}
+	}
+
+	return "", nil
+}
+
+// valueFlags are the flags of the Stripe CLI that take a value, which can be passed as a separate argument.
+var valueFlags = []string{
+	"--api-key", "--color", "--config", "--device-name", "--log-level", "--project-name", "-p",
+	"--data", "-d", "--expand", "-e", "--idempotency", "-i", "--stripe-account", "--stripe-version",
+}
+
+// parseFlags returns the flags on the command line, as "--flag" or "--flag=value". Arguments after "--" and the
+// values of flags are not flags, even if they start with "-".
+func parseFlags(commandLine []string) []string {
+	var flags []string
+	for i := 1; i < len(commandLine); i++ {
+		arg := commandLine[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if !strings.Contains(arg, "=") && isValueFlag(arg) && i+1 < len(commandLine) {
+			flags = append(flags, arg+"="+commandLine[i+1])
+			i++
+			continue
+		}
+		flags = append(flags, arg)
+	}
+	return flags
+}
+
+func isValueFlag(flag string) bool {
+	for _, valueFlag := range valueFlags {
+		if flag == valueFlag {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag || f == flag+"=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns the value of the flag with one of the names.
+func flagValue(flags []string, names ...string) (string, bool) {
+	for _, f := range flags {
+		for _, name := range names {
+			if strings.HasPrefix(f, name+"=") {
+				return strings.TrimPrefix(f, name+"="), true
+			}
+		}
+	}
+	return "", false
+}
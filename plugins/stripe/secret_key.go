@@ -6,7 +6,6 @@ import (
 
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/importer"
-	"github.com/1Password/shell-plugins/sdk/provision"
 	"github.com/1Password/shell-plugins/sdk/schema"
 	"github.com/1Password/shell-plugins/sdk/schema/credname"
 	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
@@ -21,11 +20,12 @@ func SecretKey() schema.CredentialType {
 		Fields: []schema.CredentialField{
 			{
 				Name:                fieldname.Key,
-				MarkdownDescription: "Key used to authenticate to Stripe.",
+				MarkdownDescription: "Secret or restricted key used to authenticate to Stripe.",
 				Secret:              true,
 				Composition: &schema.ValueComposition{
-					Length: 107,
-					Prefix: "sk_",
+					MinLength: 32,
+					MaxLength: 255,
+					Prefixes:  keyPrefixes,
 					Charset: schema.Charset{
 						Uppercase: true,
 						Lowercase: true,
@@ -37,9 +37,15 @@ func SecretKey() schema.CredentialType {
 				Name:                fieldname.Mode,
 				MarkdownDescription: "Whether the key can be used for live or test mode.",
 				Optional:            true,
+				Enum:                []string{ModeLive, ModeTest},
+			},
+			{
+				Name:                fieldname.Project,
+				MarkdownDescription: "The Stripe CLI project (`--project-name`) to use this key for.",
+				Optional:            true,
 			},
 		},
-		DefaultProvisioner: provision.EnvVars(defaultEnvVarMapping),
+		DefaultProvisioner: StripeProvisioner(),
 		Importer: importer.TryAll(
 			importer.TryEnvVarPair(defaultEnvVarMapping),
 			importer.TryAllEnvVars(fieldname.Key, "STRIPE_SECRET_KEY"),
@@ -53,6 +59,20 @@ const (
 	ModeTest = "test"
 )
 
+// keyPrefixes are the prefixes of the keys that can be used to authenticate the Stripe CLI: secret keys and
+// restricted keys, which include the mode they can be used for. Publishable keys (pk_) can't be used to make
+// changes, so they're not supported. Test mode prefixes come first, so that example keys are test mode keys.
+var keyPrefixes = []string{"sk_test_", "sk_live_", "rk_test_", "rk_live_"}
+
+func isSupportedKey(key string) bool {
+	for _, prefix := range keyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 var defaultEnvVarMapping = map[string]sdk.FieldName{
 	"STRIPE_API_KEY": fieldname.Key,
 }
@@ -86,18 +106,13 @@ func TryStripeConfigFile() sdk.Importer {
 
 			nameHint := importer.SanitizeNameHint(project)
 
-			// We only support secret keys for now.
-			// Support for publishable and restricted keys will be added later.
-			if strings.HasPrefix(config.LiveModeAPIKey, "sk_") {
+			if isSupportedKey(config.LiveModeAPIKey) {
 				out.AddCandidate(sdk.ImportCandidate{
-					Fields: map[sdk.FieldName]string{
-						fieldname.Key:  config.LiveModeAPIKey,
-						fieldname.Mode: ModeLive,
-					},
+					Fields:   projectFields(project, config.LiveModeAPIKey, ModeLive),
 					NameHint: nameHint,
 				})
 			}
-			if strings.HasPrefix(config.TestModeAPIKey, "sk_") {
+			if isSupportedKey(config.TestModeAPIKey) {
 				// Mention "test" in name hint
 				if nameHint != "" {
 					nameHint += " - "
@@ -105,13 +120,23 @@ func TryStripeConfigFile() sdk.Importer {
 				nameHint += "test"
 
 				out.AddCandidate(sdk.ImportCandidate{
-					Fields: map[sdk.FieldName]string{
-						fieldname.Key:  config.TestModeAPIKey,
-						fieldname.Mode: ModeTest,
-					},
+					Fields:   projectFields(project, config.TestModeAPIKey, ModeTest),
 					NameHint: nameHint,
 				})
 			}
 		}
 	})
 }
+
+// projectFields returns the fields for a key of the project. The project is only included if it's not the default
+// project, so that the item can be used without having to pass --project-name.
+func projectFields(project string, key string, mode string) map[sdk.FieldName]string {
+	fields := map[sdk.FieldName]string{
+		fieldname.Key:  key,
+		fieldname.Mode: mode,
+	}
+	if project != "default" {
+		fields[fieldname.Project] = project
+	}
+	return fields
+}
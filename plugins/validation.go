@@ -0,0 +1,291 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/credname"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+// ValidateRegistry cross-checks the specified plugins against each other and against the shared field and credential
+// names, which Plugin.DeepValidate can't do since it only looks at a single plugin. Returns a report for each plugin,
+// by plugin name.
+func ValidateRegistry(list []schema.Plugin) map[string]schema.ValidationReport {
+	reports := make(map[string]schema.ValidationReport)
+	for _, p := range list {
+		report := schema.ValidationReport{
+			Heading: fmt.Sprintf("Registry: %s", p.Name),
+			Checks:  []schema.ValidationCheck{},
+		}
+
+		validateNames(p, &report)
+		validateCredentialUsages(p, list, &report)
+		validateExecutableNames(p, list, &report)
+		for _, cred := range p.Credentials {
+			validateEnvVarSchema(fmt.Sprintf("default provisioner of '%s'", cred.Name), cred, cred.DefaultProvisioner, &report)
+			validateImportedFields(cred, &report)
+		}
+
+		reports[p.Name] = report
+	}
+	return reports
+}
+
+// validateNames checks whether the field and credential names come from the shared lists, so the same field or
+// credential is called the same across plugins.
+func validateNames(p schema.Plugin, report *schema.ValidationReport) {
+	for _, cred := range p.Credentials {
+		report.AddCheck(schema.ValidationCheck{
+			ID:          "registry-credential-name",
+			Subject:     cred.Name.String(),
+			Description: fmt.Sprintf("Credential name '%s' is defined in the credname package", cred.Name),
+			Assertion:   containsCredentialName(credname.ListAll(), cred.Name),
+			Severity:    schema.ValidationSeverityError,
+		})
+
+		for _, field := range cred.Fields {
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "registry-field-name",
+				Subject:     field.Name.String(),
+				Description: fmt.Sprintf("Field name '%s' of credential '%s' is defined in the fieldname package", field.Name, cred.Name),
+				Assertion:   containsFieldName(fieldname.ListAll(), field.Name),
+				Severity:    schema.ValidationSeverityError,
+			})
+		}
+	}
+}
+
+// validateCredentialUsages checks whether the credentials the executables use exist, in the plugin itself or in the
+// plugin that's referred to. Provisioners that override the default provisioner are checked against that credential.
+func validateCredentialUsages(p schema.Plugin, list []schema.Plugin, report *schema.ValidationReport) {
+	for _, exe := range p.Executables {
+		for _, usage := range exe.Uses {
+			pluginName := usage.Plugin
+			if pluginName == "" {
+				pluginName = p.Name
+			}
+
+			cred, ok := findCredential(list, pluginName, usage.Name)
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "registry-credential-usage",
+				Subject:     usage.Name.String(),
+				Description: fmt.Sprintf("Credential '%s' used by executable '%s' exists in plugin '%s'", usage.Name, exe.Name, pluginName),
+				Assertion:   ok,
+				Severity:    schema.ValidationSeverityError,
+			})
+
+			if ok && usage.Provisioner != nil {
+				validateEnvVarSchema(fmt.Sprintf("provisioner of '%s' for executable '%s'", cred.Name, exe.Name), cred, usage.Provisioner, report)
+			}
+		}
+	}
+}
+
+// validateExecutableNames checks whether no other plugin runs the same executable, since the shell plugin for an
+// executable is looked up by its name.
+func validateExecutableNames(p schema.Plugin, list []schema.Plugin, report *schema.ValidationReport) {
+	for _, exe := range p.Executables {
+		if len(exe.Runs) == 0 {
+			continue
+		}
+
+		var others []string
+		for _, other := range list {
+			if other.Name == p.Name {
+				continue
+			}
+			for _, otherExe := range other.Executables {
+				if len(otherExe.Runs) > 0 && otherExe.Runs[0] == exe.Runs[0] {
+					others = append(others, other.Name)
+				}
+			}
+		}
+
+		description := fmt.Sprintf("Executable '%s' is not claimed by another plugin", exe.Runs[0])
+		if len(others) > 0 {
+			description += fmt.Sprintf(", but is also claimed by: %s", strings.Join(others, ", "))
+		}
+		report.AddCheck(schema.ValidationCheck{
+			ID:          "registry-executable-unique",
+			Subject:     exe.Runs[0],
+			Description: description,
+			Assertion:   len(others) == 0,
+			Severity:    schema.ValidationSeverityError,
+		})
+	}
+}
+
+// validateEnvVarSchema checks whether the environment variables the provisioner maps fields to refer to fields that
+// exist on the credential.
+func validateEnvVarSchema(provisionerDescription string, cred schema.CredentialType, provisioner sdk.Provisioner, report *schema.ValidationReport) {
+	for _, envVarSchema := range envVarSchemas(provisioner) {
+		for _, envVarName := range sortedKeys(envVarSchema) {
+			fieldName := envVarSchema[envVarName]
+			report.AddCheck(schema.ValidationCheck{
+				ID:          "registry-env-var-field",
+				Subject:     envVarName,
+				Description: fmt.Sprintf("Environment variable %s of the %s maps to existing field '%s'", envVarName, provisionerDescription, fieldName),
+				Assertion:   hasField(cred, fieldName),
+				Severity:    schema.ValidationSeverityError,
+			})
+		}
+	}
+}
+
+// validateImportedFields runs the importer against an empty root dir and an environment with only the environment
+// variables of the default provisioner set, and checks whether the candidates it returns only have fields that exist
+// on the credential. Importers that only look at files won't return candidates this way, so those can't be checked.
+func validateImportedFields(cred schema.CredentialType, report *schema.ValidationReport) {
+	if cred.Importer == nil {
+		return
+	}
+
+	unknown, err := unknownImportedFields(cred)
+	if err != nil {
+		report.AddCheck(schema.ValidationCheck{
+			ID:          "registry-importer-run",
+			Subject:     cred.Name.String(),
+			Description: fmt.Sprintf("Importer of '%s' can be run to check the imported fields: %s", cred.Name, err),
+			Assertion:   false,
+			Severity:    schema.ValidationSeverityWarning,
+		})
+		return
+	}
+
+	description := fmt.Sprintf("Importer of '%s' only imports fields that exist on the credential", cred.Name)
+	if len(unknown) > 0 {
+		description += fmt.Sprintf(", but imports: %s", strings.Join(unknown, ", "))
+	}
+	report.AddCheck(schema.ValidationCheck{
+		ID:          "registry-importer-fields",
+		Subject:     cred.Name.String(),
+		Description: description,
+		Assertion:   len(unknown) == 0,
+		Severity:    schema.ValidationSeverityError,
+	})
+}
+
+func unknownImportedFields(cred schema.CredentialType) ([]string, error) {
+	root, err := os.MkdirTemp("", "shell-plugins-validation-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(root)
+
+	out := sdk.ImportOutput{}
+	cred.Importer(context.Background(), sdk.ImportInput{
+		HomeDir:     root,
+		RootDir:     root,
+		Environment: exampleEnvironment(cred),
+	}, &out)
+
+	var unknown []string
+	for _, candidate := range out.AllCandidates() {
+		for fieldName := range candidate.Fields {
+			name := fmt.Sprintf("'%s'", fieldName)
+			if !hasField(cred, fieldName) && !containsString(unknown, name) {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// exampleEnvironment returns the environment variables of the default provisioner of the credential, with example
+// values for the fields they map to.
+func exampleEnvironment(cred schema.CredentialType) map[string]string {
+	env := make(map[string]string)
+	for _, envVarSchema := range envVarSchemas(cred.DefaultProvisioner) {
+		for envVarName, fieldName := range envVarSchema {
+			value := "example"
+			if field := cred.Field(fieldName.String()); field != nil {
+				if example := plugintest.ExampleFieldValue(*field); example != "" {
+					value = example
+				}
+			}
+			env[envVarName] = value
+		}
+	}
+	return env
+}
+
+// envVarSchemas returns the environment variable schemas of the provisioner, including those of the provisioners
+// it chains.
+func envVarSchemas(provisioner sdk.Provisioner) []map[string]sdk.FieldName {
+	switch p := provisioner.(type) {
+	case provision.EnvVarProvisioner:
+		return []map[string]sdk.FieldName{p.Schema}
+	case *provision.EnvVarProvisioner:
+		return []map[string]sdk.FieldName{p.Schema}
+	case provision.ChainedProvisioner:
+		var schemas []map[string]sdk.FieldName
+		for _, chained := range p.Provisioners {
+			schemas = append(schemas, envVarSchemas(chained)...)
+		}
+		return schemas
+	}
+	return nil
+}
+
+func findCredential(list []schema.Plugin, pluginName string, credentialName sdk.CredentialName) (schema.CredentialType, bool) {
+	for _, p := range list {
+		if p.Name != pluginName {
+			continue
+		}
+		for _, cred := range p.Credentials {
+			if cred.Name == credentialName {
+				return cred, true
+			}
+		}
+	}
+	return schema.CredentialType{}, false
+}
+
+func hasField(cred schema.CredentialType, name sdk.FieldName) bool {
+	return cred.Field(name.String()) != nil
+}
+
+func containsFieldName(names []sdk.FieldName, name sdk.FieldName) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCredentialName(names []sdk.CredentialName, name sdk.CredentialName) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]sdk.FieldName) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
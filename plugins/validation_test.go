@@ -0,0 +1,137 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/credname"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPlugin(name string, executable string, cred schema.CredentialType, uses ...schema.CredentialUsage) schema.Plugin {
+	if len(uses) == 0 {
+		uses = []schema.CredentialUsage{{Name: cred.Name}}
+	}
+	return schema.Plugin{
+		Name:        name,
+		Credentials: []schema.CredentialType{cred},
+		Executables: []schema.Executable{
+			{
+				Name: name + " CLI",
+				Runs: []string{executable},
+				Uses: uses,
+			},
+		},
+	}
+}
+
+func testCredential(name sdk.CredentialName, fields ...sdk.FieldName) schema.CredentialType {
+	cred := schema.CredentialType{Name: name}
+	for _, field := range fields {
+		cred.Fields = append(cred.Fields, schema.CredentialField{Name: field})
+	}
+	return cred
+}
+
+// registryReportList returns the reports of ValidateRegistry as a list.
+func registryReportList(reports map[string]schema.ValidationReport) []schema.ValidationReport {
+	var list []schema.ValidationReport
+	for _, report := range reports {
+		list = append(list, report)
+	}
+	return list
+}
+
+// failedChecks returns the descriptions of the failed checks per report heading.
+func failedChecks(reports []schema.ValidationReport) map[string][]string {
+	failed := make(map[string][]string)
+	for _, report := range reports {
+		for _, check := range report.Checks {
+			if !check.Assertion {
+				failed[report.Heading] = append(failed[report.Heading], check.Description)
+			}
+		}
+	}
+	return failed
+}
+
+func TestValidateRegistryValid(t *testing.T) {
+	token := testCredential(credname.APIToken, fieldname.Token)
+	token.DefaultProvisioner = provision.EnvVars(map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token})
+	token.Importer = importer.TryEnvVarPair(map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token})
+
+	list := []schema.Plugin{
+		testPlugin("foo", "foo", token),
+		testPlugin("bar", "bar", testCredential(credname.AccessKey, fieldname.AccessKeyID, fieldname.SecretAccessKey),
+			schema.CredentialUsage{Name: credname.AccessKey},
+			schema.CredentialUsage{Name: credname.APIToken, Plugin: "foo"},
+		),
+	}
+
+	assert.Empty(t, failedChecks(registryReportList(ValidateRegistry(list))))
+}
+
+func TestValidateRegistryErrors(t *testing.T) {
+	token := testCredential("Magic Token", fieldname.Token, "Magic Number")
+	token.DefaultProvisioner = provision.All(
+		provision.EnvVars(map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token}),
+		provision.EnvVars(map[string]sdk.FieldName{"FOO_HOST": fieldname.Host}),
+	)
+	token.Importer = func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		out.NewAttempt(importer.SourceFile("~/.foo")).AddCandidate(sdk.ImportCandidate{
+			Fields: map[sdk.FieldName]string{fieldname.Token: "token", fieldname.Password: "password"},
+		})
+	}
+
+	list := []schema.Plugin{
+		testPlugin("foo", "foo", token,
+			schema.CredentialUsage{Name: credname.APIToken},
+			schema.CredentialUsage{
+				Name:        credname.APIKey,
+				Plugin:      "bar",
+				Provisioner: provision.EnvVars(map[string]sdk.FieldName{"BAR_KEY": fieldname.Key}),
+			},
+		),
+		testPlugin("bar", "foo", testCredential(credname.APIKey, fieldname.APIKey)),
+	}
+
+	assert.Equal(t, map[string][]string{
+		"Registry: foo": {
+			"Credential name 'Magic Token' is defined in the credname package",
+			"Field name 'Magic Number' of credential 'Magic Token' is defined in the fieldname package",
+			"Credential 'API Token' used by executable 'foo CLI' exists in plugin 'foo'",
+			"Environment variable BAR_KEY of the provisioner of 'API Key' for executable 'foo CLI' maps to existing field 'Key'",
+			"Executable 'foo' is not claimed by another plugin, but is also claimed by: bar",
+			"Environment variable FOO_HOST of the default provisioner of 'Magic Token' maps to existing field 'Host'",
+			"Importer of 'Magic Token' only imports fields that exist on the credential, but imports: 'Password'",
+		},
+		"Registry: bar": {
+			"Executable 'foo' is not claimed by another plugin, but is also claimed by: foo",
+		},
+	}, failedChecks(registryReportList(ValidateRegistry(list))))
+}
+
+func TestValidateRegistryImporterEnvironment(t *testing.T) {
+	t.Setenv("FOO_HOST", "localhost")
+
+	token := testCredential(credname.APIToken, fieldname.Token)
+	token.DefaultProvisioner = provision.EnvVars(map[string]sdk.FieldName{"FOO_TOKEN": fieldname.Token})
+
+	var in sdk.ImportInput
+	token.Importer = func(ctx context.Context, importInput sdk.ImportInput, out *sdk.ImportOutput) {
+		in = importInput
+		assert.Empty(t, os.Getenv("FOO_TOKEN"), "the process environment should not be changed")
+	}
+
+	ValidateRegistry([]schema.Plugin{testPlugin("foo", "foo", token)})
+
+	assert.NotEmpty(t, in.Getenv("FOO_TOKEN"))
+	assert.Empty(t, in.Getenv("FOO_HOST"), "the importer should not see the process environment")
+	assert.NoDirExists(t, in.RootDir, "the root dir should be removed afterwards")
+}
@@ -3,7 +3,6 @@ package vault
 import (
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/importer"
-	"github.com/1Password/shell-plugins/sdk/provision"
 	"github.com/1Password/shell-plugins/sdk/schema"
 	"github.com/1Password/shell-plugins/sdk/schema/credname"
 	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
@@ -17,21 +16,51 @@ func AuthToken() schema.CredentialType {
 		Fields: []schema.CredentialField{
 			{
 				Name:                fieldname.Token,
-				MarkdownDescription: "Token used to authenticate to HashiCorp Vault.",
+				MarkdownDescription: "Token used to authenticate to HashiCorp Vault. Not needed when logging in with AppRole or userpass.",
 				Secret:              true,
+				Optional:            true,
 			},
 			{
 				Name:                fieldname.Address,
 				MarkdownDescription: "Default address of the Vault server to use for this auth token.",
 				Optional:            true,
+				Type:                schema.FieldTypeURL,
+				Placeholder:         "https://vault.example.com:8200",
 			},
 			{
 				Name:                fieldname.Namespace,
 				MarkdownDescription: "Default namespace to use for this auth token.",
 				Optional:            true,
 			},
+			{
+				Name:                fieldname.RoleID,
+				MarkdownDescription: "Role ID to log in with using the AppRole auth method, instead of using a token.",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.SecretID,
+				MarkdownDescription: "Secret ID to log in with using the AppRole auth method.",
+				Secret:              true,
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.Username,
+				MarkdownDescription: "Username to log in with using the userpass auth method, instead of using a token.",
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.Password,
+				MarkdownDescription: "Password to log in with using the userpass auth method.",
+				Secret:              true,
+				Optional:            true,
+			},
+			{
+				Name:                fieldname.Duration,
+				MarkdownDescription: "How long the short-lived token minted after logging in with AppRole or userpass should be valid, e.g. `30m`. Defaults to 15 minutes.",
+				Optional:            true,
+			},
 		},
-		DefaultProvisioner: provision.EnvVars(defaultEnvVarMapping),
+		DefaultProvisioner: VaultProvisioner(),
 		Importer: importer.TryAll(
 			importer.TryEnvVarPair(defaultEnvVarMapping),
 			TryVaultTokenFile(),
@@ -44,8 +73,3 @@ var defaultEnvVarMapping = map[string]sdk.FieldName{
 	"VAULT_ADDR":      fieldname.Address,
 	"VAULT_NAMESPACE": fieldname.Namespace,
 }
-
-func TryVaultTokenFile() sdk.Importer {
-	// TODO: Try importing from ~/.vault-token file
-	return importer.NoOp()
-}
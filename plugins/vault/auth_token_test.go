@@ -1,11 +1,15 @@
 package vault
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/1Password/shell-plugins/sdk/plugintest"
 	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAuthTokenImporter(t *testing.T) {
@@ -26,9 +30,70 @@ func TestAuthTokenImporter(t *testing.T) {
 				},
 			},
 		},
+		"token file": {
+			Files: map[string]string{
+				"~/.vault-token": plugintest.LoadFixture(t, "vault-token"),
+			},
+			ExpectedCandidates: []sdk.ImportCandidate{
+				{
+					Fields: map[sdk.FieldName]string{
+						fieldname.Token: "hvs.CAESIJlWh4aW5mLXJ0Zm9yZXN0EXAMPLE",
+					},
+				},
+			},
+		},
 	})
 }
 
+func TestAuthTokenImporterTokenHelper(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "helper-ran")
+	helper := filepath.Join(dir, "vault-token-helper")
+	err := os.WriteFile(helper, []byte("#!/bin/sh\ntouch "+marker+"\necho hvs.CAESIHelperTokenEXAMPLE\n"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsRoot := t.TempDir()
+	in := sdk.ImportInput{HomeDir: fsRoot, RootDir: fsRoot}
+	err = os.WriteFile(filepath.Join(fsRoot, ".vault"), []byte(`token_helper = "`+helper+`"`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(fsRoot, ".vault-token"), []byte("hvs.CAESIJlWh4aW5mLXJ0Zm9yZXN0EXAMPLE"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := sdk.ImportOutput{}
+	TryVaultTokenFile()(context.Background(), in, &out)
+
+	assert.Empty(t, out.AllCandidates(), "the token file is not used when a token helper is configured")
+	assert.Empty(t, out.Errors())
+	assert.Equal(t, []sdk.Warning{{Message: "The Vault CLI stores its token with the token helper " + helper + ", which doesn't get run, so the token can't be imported: run `vault print token` and save the token in 1Password yourself"}}, out.Warnings())
+	if assert.Len(t, out.Attempts, 1) {
+		assert.Equal(t, sdk.ImportSource{Files: []string{"~/.vault"}}, out.Attempts[0].Source)
+	}
+	assert.NoFileExists(t, marker, "the token helper should not be run")
+}
+
+func TestAuthTokenImporterConfigPathInRootDir(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "vault.hcl")
+	err := os.WriteFile(outside, []byte(`token_helper = "/usr/local/bin/vault-token-helper"`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VAULT_CONFIG_PATH", outside)
+
+	fsRoot := t.TempDir()
+	out := sdk.ImportOutput{}
+	TryVaultTokenFile()(context.Background(), sdk.ImportInput{HomeDir: fsRoot, RootDir: fsRoot}, &out)
+
+	for _, attempt := range out.Attempts {
+		assert.NotContains(t, attempt.Source.Files, "/usr/local/bin/vault-token-helper", "config files outside of the root dir should not be read")
+	}
+}
+
 func TestAuthTokenProvisioner(t *testing.T) {
 	plugintest.TestProvisioner(t, AuthToken().DefaultProvisioner, map[string]plugintest.ProvisionCase{
 		"default": {
@@ -45,5 +110,15 @@ func TestAuthTokenProvisioner(t *testing.T) {
 				},
 			},
 		},
+		"no token or login credential": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.Address: "https://vault.acme.com",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Diagnostics: sdk.Diagnostics{
+					Errors: []sdk.Error{{Message: "the 1Password item has no token or login credential: set 'Token', or 'Role ID' and 'Secret ID', or 'Username' and 'Password'"}},
+				},
+			},
+		},
 	})
 }
@@ -0,0 +1,284 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+// AuthMethod is a Vault auth method that can be used to log in with a credential stored in 1Password.
+type AuthMethod string
+
+const (
+	AppRole  AuthMethod = "approle"
+	UserPass AuthMethod = "userpass"
+)
+
+const (
+	defaultChildTokenTTL = 15 * time.Minute
+	loginCacheKeyBase    = "vault-login"
+	childTokenFilename   = "vault-child-token.json"
+	childTokenName       = "1password-shell-plugin"
+)
+
+// LoginProvisioner logs in to Vault using an AppRole or userpass credential and provisions a short-lived child
+// token of the resulting login token as VAULT_TOKEN. The login token gets cached, so that consecutive runs only
+// have to mint a new child token. The child token gets revoked when the executable exits.
+type LoginProvisioner struct {
+	Method AuthMethod
+
+	// (Optional) The path the auth method is mounted at. Defaults to the name of the auth method.
+	Mount string
+
+	// (Optional) How long the child token should be valid. Defaults to 15 minutes.
+	TTL time.Duration
+
+	// (Optional) The HTTP client to use to talk to Vault. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// loginToken is the login token as it gets stored in the cache.
+type loginToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// childToken is the child token as it gets stored in the temp dir, so that it can be revoked in Deprovision.
+type childToken struct {
+	Address   string
+	Namespace string
+	Token     string
+}
+
+type vaultAuthResponse struct {
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func (p LoginProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	address := in.ItemFields[fieldname.Address]
+	if address == "" {
+		out.AddError(fmt.Errorf("address is required to log in to Vault: set '%s' in 1Password", fieldname.Address))
+		return
+	}
+	address = strings.TrimSuffix(address, "/")
+	namespace := in.ItemFields[fieldname.Namespace]
+
+	ttl := p.TTL
+	if ttl == 0 {
+		ttl = defaultChildTokenTTL
+	}
+
+	cacheKey := p.cacheKey(address, namespace, in.ItemFields)
+
+	var child string
+	var cached loginToken
+	// Only use the cached login token if it outlives the child token, since the child token expires with its parent
+	if ok := in.Cache.Get(cacheKey, &cached); ok && time.Until(cached.ExpiresAt) > ttl {
+		var err error
+		child, err = p.createChildToken(ctx, address, namespace, cached.Token, ttl)
+		if err != nil {
+			// The login token may have been revoked in the meantime, so log in again
+			out.Cache.Remove(cacheKey)
+		}
+	}
+
+	if child == "" {
+		login, err := p.login(ctx, address, namespace, in.ItemFields)
+		if err != nil {
+			out.AddError(err)
+			return
+		}
+
+		child, err = p.createChildToken(ctx, address, namespace, login.Token, ttl)
+		if err != nil {
+			out.AddError(err)
+			return
+		}
+
+		if !login.ExpiresAt.IsZero() {
+			err = out.Cache.Put(cacheKey, login, login.ExpiresAt)
+			if err != nil {
+				out.AddError(fmt.Errorf("failed to serialize vault login token: %w", err))
+				return
+			}
+		}
+	}
+
+	revocation, err := json.Marshal(childToken{Address: address, Namespace: namespace, Token: child})
+	if err != nil {
+		out.AddError(err)
+		return
+	}
+	out.AddSecretFile(in.FromTempDir(childTokenFilename), revocation)
+
+	out.AddEnvVar("VAULT_TOKEN", child)
+	out.AddEnvVar("VAULT_ADDR", address)
+	if namespace != "" {
+		out.AddEnvVar("VAULT_NAMESPACE", namespace)
+	}
+}
+
+func (p LoginProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	if in.DryRun {
+		return
+	}
+
+	contents, err := os.ReadFile(in.FromTempDir(childTokenFilename))
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		out.AddError(err)
+		return
+	}
+
+	var child childToken
+	if err := json.Unmarshal(contents, &child); err != nil {
+		out.AddError(fmt.Errorf("failed to read vault child token: %w", err))
+		return
+	}
+
+	err = p.post(ctx, child.Address, child.Namespace, child.Token, "auth/token/revoke-self", nil, nil)
+	if err != nil {
+		out.AddError(fmt.Errorf("revoking vault child token: %w", err))
+	}
+}
+
+func (p LoginProvisioner) Description() string {
+	return fmt.Sprintf("Log in to Vault using %s and provision a short-lived child token as VAULT_TOKEN, which gets revoked afterwards", p.Method)
+}
+
+func (p LoginProvisioner) mount() string {
+	if p.Mount != "" {
+		return strings.Trim(p.Mount, "/")
+	}
+	return string(p.Method)
+}
+
+// cacheKey keys the cache by server, namespace and identity, so that changing either logs in again.
+func (p LoginProvisioner) cacheKey(address string, namespace string, fields map[sdk.FieldName]string) string {
+	identity := fields[fieldname.Username]
+	if p.Method == AppRole {
+		identity = fields[fieldname.RoleID]
+	}
+	return fmt.Sprintf("%s:%s:%s:%s:%s", loginCacheKeyBase, address, namespace, p.mount(), identity)
+}
+
+func (p LoginProvisioner) login(ctx context.Context, address string, namespace string, fields map[sdk.FieldName]string) (loginToken, error) {
+	var path string
+	var body map[string]string
+	switch p.Method {
+	case AppRole:
+		path = fmt.Sprintf("auth/%s/login", p.mount())
+		body = map[string]string{
+			"role_id":   fields[fieldname.RoleID],
+			"secret_id": fields[fieldname.SecretID],
+		}
+	case UserPass:
+		path = fmt.Sprintf("auth/%s/login/%s", p.mount(), url.PathEscape(fields[fieldname.Username]))
+		body = map[string]string{
+			"password": fields[fieldname.Password],
+		}
+	default:
+		return loginToken{}, fmt.Errorf("unsupported vault auth method '%s'", p.Method)
+	}
+
+	var resp vaultAuthResponse
+	if err := p.post(ctx, address, namespace, "", path, body, &resp); err != nil {
+		return loginToken{}, fmt.Errorf("logging in to vault using %s: %w", p.Method, err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return loginToken{}, fmt.Errorf("logging in to vault using %s: no token in response", p.Method)
+	}
+
+	login := loginToken{Token: resp.Auth.ClientToken}
+	if resp.Auth.LeaseDuration > 0 {
+		login.ExpiresAt = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+
+	return login, nil
+}
+
+func (p LoginProvisioner) createChildToken(ctx context.Context, address string, namespace string, parent string, ttl time.Duration) (string, error) {
+	body := map[string]any{
+		"ttl":          fmt.Sprintf("%ds", int(ttl.Seconds())),
+		"display_name": childTokenName,
+		"renewable":    false,
+	}
+
+	var resp vaultAuthResponse
+	if err := p.post(ctx, address, namespace, parent, "auth/token/create", body, &resp); err != nil {
+		return "", fmt.Errorf("creating vault child token: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", errors.New("creating vault child token: no token in response")
+	}
+
+	return resp.Auth.ClientToken, nil
+}
+
+// post sends a request to the Vault HTTP API and decodes the response into result, if specified.
+func (p LoginProvisioner) post(ctx context.Context, address string, namespace string, token string, path string, body any, result any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/%s", address, path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp vaultErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		if len(errResp.Errors) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, strings.Join(errResp.Errors, "; "))
+		}
+		return errors.New(resp.Status)
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
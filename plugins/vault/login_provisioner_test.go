@@ -0,0 +1,245 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault implements the login, token creation and revocation endpoints of the Vault HTTP API.
+type fakeVault struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	logins  int
+	revoked []string
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	fake := &fakeVault{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "db02de05-fa39-4855-059b-67221c5c2f63" || body["secret_id"] != "6a174c20-f6de-a53c-74d2-6018fcceff64" {
+			fake.writeError(w, http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		fake.mu.Lock()
+		fake.logins++
+		fake.mu.Unlock()
+		fake.writeAuth(w, "hvs.login-token", 3600)
+	})
+	mux.HandleFunc("/v1/auth/userpass/login/wendy", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["password"] != "correct-horse-battery-staple" {
+			fake.writeError(w, http.StatusBadRequest, "invalid username or password")
+			return
+		}
+		fake.mu.Lock()
+		fake.logins++
+		fake.mu.Unlock()
+		fake.writeAuth(w, "hvs.login-token", 3600)
+	})
+	mux.HandleFunc("/v1/auth/token/create", func(w http.ResponseWriter, r *http.Request) {
+		parent := r.Header.Get("X-Vault-Token")
+		if parent != "hvs.login-token" {
+			fake.writeError(w, http.StatusForbidden, "permission denied")
+			return
+		}
+		fake.writeAuth(w, "hvs.child-token", 900)
+	})
+	mux.HandleFunc("/v1/auth/token/revoke-self", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.revoked = append(fake.revoked, r.Header.Get("X-Vault-Token"))
+		fake.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fake.Server = httptest.NewServer(mux)
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeVault) writeAuth(w http.ResponseWriter, token string, leaseDuration int) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"auth": map[string]any{
+			"client_token":   token,
+			"lease_duration": leaseDuration,
+		},
+	})
+}
+
+func (f *fakeVault) writeError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{message}})
+}
+
+func TestVaultProvisionerAppRole(t *testing.T) {
+	fake := newFakeVault(t)
+	tempDir := t.TempDir()
+
+	in := sdk.ProvisionInput{
+		TempDir: tempDir,
+		ItemFields: map[sdk.FieldName]string{
+			fieldname.RoleID:   "db02de05-fa39-4855-059b-67221c5c2f63",
+			fieldname.SecretID: "6a174c20-f6de-a53c-74d2-6018fcceff64",
+			fieldname.Address:  fake.URL,
+		},
+	}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+
+	VaultProvisioner().Provision(context.Background(), in, &out)
+
+	require.Empty(t, out.Diagnostics.Errors)
+	assert.Equal(t, map[string]string{
+		"VAULT_TOKEN": "hvs.child-token",
+		"VAULT_ADDR":  fake.URL,
+	}, out.Environment)
+	assert.Equal(t, 1, fake.logins)
+
+	var cached loginToken
+	require.True(t, sdk.CacheState(out.Cache.Puts).Get("vault-login:"+fake.URL+"::approle:db02de05-fa39-4855-059b-67221c5c2f63", &cached))
+	assert.Equal(t, "hvs.login-token", cached.Token)
+
+	// Write the provisioned files to the temp dir, like the CLI does, so that Deprovision can revoke the child token
+	for path, file := range out.Files {
+		require.NoError(t, os.WriteFile(path, file.Contents, 0600))
+	}
+
+	var deprovisionOut sdk.DeprovisionOutput
+	VaultProvisioner().Deprovision(context.Background(), sdk.DeprovisionInput{TempDir: tempDir}, &deprovisionOut)
+
+	assert.Empty(t, deprovisionOut.Diagnostics.Errors)
+	assert.Equal(t, []string{"hvs.child-token"}, fake.revoked)
+}
+
+func TestVaultProvisionerCachedLogin(t *testing.T) {
+	fake := newFakeVault(t)
+
+	p := LoginProvisioner{Method: UserPass}
+	fields := map[sdk.FieldName]string{
+		fieldname.Username: "wendy",
+		fieldname.Password: "correct-horse-battery-staple",
+		fieldname.Address:  fake.URL,
+	}
+
+	cacheOps := sdk.CacheOperations{Puts: make(sdk.CacheState)}
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, cacheOps.Put(p.cacheKey(fake.URL, "", fields), loginToken{Token: "hvs.login-token", ExpiresAt: expiresAt}, expiresAt))
+
+	in := sdk.ProvisionInput{TempDir: t.TempDir(), ItemFields: fields, Cache: cacheOps.Puts}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+
+	p.Provision(context.Background(), in, &out)
+
+	require.Empty(t, out.Diagnostics.Errors)
+	assert.Equal(t, "hvs.child-token", out.Environment["VAULT_TOKEN"])
+	assert.Equal(t, 0, fake.logins, "the cached login token should be used")
+}
+
+func TestVaultProvisionerRevokedCachedLogin(t *testing.T) {
+	fake := newFakeVault(t)
+
+	p := LoginProvisioner{Method: UserPass}
+	fields := map[sdk.FieldName]string{
+		fieldname.Username: "wendy",
+		fieldname.Password: "correct-horse-battery-staple",
+		fieldname.Address:  fake.URL,
+	}
+
+	cacheOps := sdk.CacheOperations{Puts: make(sdk.CacheState)}
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, cacheOps.Put(p.cacheKey(fake.URL, "", fields), loginToken{Token: "hvs.revoked-token", ExpiresAt: expiresAt}, expiresAt))
+
+	in := sdk.ProvisionInput{TempDir: t.TempDir(), ItemFields: fields, Cache: cacheOps.Puts}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+
+	p.Provision(context.Background(), in, &out)
+
+	require.Empty(t, out.Diagnostics.Errors)
+	assert.Equal(t, "hvs.child-token", out.Environment["VAULT_TOKEN"])
+	assert.Equal(t, 1, fake.logins, "a revoked login token should trigger a new login")
+}
+
+func TestVaultProvisionerInvalidLogin(t *testing.T) {
+	fake := newFakeVault(t)
+
+	in := sdk.ProvisionInput{
+		TempDir: t.TempDir(),
+		ItemFields: map[sdk.FieldName]string{
+			fieldname.Username: "wendy",
+			fieldname.Password: "wrong",
+			fieldname.Address:  fake.URL,
+		},
+	}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+
+	VaultProvisioner().Provision(context.Background(), in, &out)
+
+	assert.Equal(t, []sdk.Error{{Message: "logging in to vault using userpass: 400 Bad Request: invalid username or password"}}, out.Diagnostics.Errors)
+	assert.Empty(t, out.Environment)
+}
+
+func TestVaultProvisionerLoginWithoutAddress(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.acme.com")
+
+	in := sdk.ProvisionInput{
+		TempDir: t.TempDir(),
+		ItemFields: map[sdk.FieldName]string{
+			fieldname.Username: "wendy",
+			fieldname.Password: "secret",
+		},
+	}
+	out := sdk.ProvisionOutput{
+		Environment: make(map[string]string),
+		Files:       make(map[string]sdk.OutputFile),
+		Cache:       sdk.CacheOperations{Puts: make(sdk.CacheState)},
+	}
+
+	VaultProvisioner().Provision(context.Background(), in, &out)
+
+	assert.Equal(t, []sdk.Error{{Message: "address is required to log in to Vault: set 'Address' in 1Password"}}, out.Diagnostics.Errors, "the address should only be taken from the item")
+}
+
+func TestParseTTL(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"900": 15 * time.Minute,
+		"30m": 30 * time.Minute,
+	} {
+		ttl, err := parseTTL(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ttl)
+	}
+
+	_, err := parseTTL("forever")
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+type vaultProvisioner struct {
+	envVarProvisioner sdk.Provisioner
+	loginProvisioner  LoginProvisioner
+}
+
+// VaultProvisioner provisions the token from the item, or, if the item contains an AppRole (Role ID and Secret ID)
+// or userpass (Username and Password) credential instead, logs in with that to provision a short-lived child token.
+// Items without a token or login credential can't be provisioned.
+func VaultProvisioner() sdk.Provisioner {
+	return vaultProvisioner{
+		envVarProvisioner: provision.EnvVars(defaultEnvVarMapping),
+	}
+}
+
+func (p vaultProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	if in.ItemFields[fieldname.Token] != "" {
+		p.envVarProvisioner.Provision(ctx, in, out)
+		return
+	}
+
+	if in.ItemFields[fieldname.RoleID] != "" && in.ItemFields[fieldname.SecretID] != "" {
+		p.loginProvisioner.Method = AppRole
+	} else if in.ItemFields[fieldname.Username] != "" && in.ItemFields[fieldname.Password] != "" {
+		p.loginProvisioner.Method = UserPass
+	} else {
+		out.AddError(fmt.Errorf("the 1Password item has no token or login credential: set '%s', or '%s' and '%s', or '%s' and '%s'", fieldname.Token, fieldname.RoleID, fieldname.SecretID, fieldname.Username, fieldname.Password))
+		return
+	}
+
+	if value := in.ItemFields[fieldname.Duration]; value != "" {
+		ttl, err := parseTTL(value)
+		if err != nil {
+			out.AddError(err)
+			return
+		}
+		p.loginProvisioner.TTL = ttl
+	}
+
+	p.loginProvisioner.Provision(ctx, in, out)
+}
+
+func (p vaultProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	// Revokes the child token if one was provisioned, and is a no-op otherwise
+	p.loginProvisioner.Deprovision(ctx, in, out)
+}
+
+func (p vaultProvisioner) Description() string {
+	return p.envVarProvisioner.Description() + ", or log in with AppRole or userpass and provision a short-lived child token as VAULT_TOKEN"
+}
+
+// parseTTL parses a TTL specified as either a number of seconds or a duration string, such as "30m".
+func parseTTL(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s': use a number of seconds or a duration such as '30m'", value)
+	}
+
+	return ttl, nil
+}
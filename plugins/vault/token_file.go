@@ -0,0 +1,90 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+const (
+	defaultConfigPath = "~/.vault"
+	defaultTokenPath  = "~/.vault-token"
+)
+
+// tokenHelperPattern matches the token_helper setting in the Vault CLI config file, which is written in HCL:
+//
+//	token_helper = "/usr/local/bin/vault-token-helper"
+var tokenHelperPattern = regexp.MustCompile(`(?m)^\s*token_helper\s*=\s*"([^"]*)"`)
+
+// TryVaultTokenFile looks for the token the Vault CLI stored after running `vault login`. By default, that's
+// the ~/.vault-token file. If a token helper is configured in the Vault CLI config file (~/.vault, or the path in
+// VAULT_CONFIG_PATH), the token is stored by the helper instead. Importers shouldn't run arbitrary binaries, so the
+// helper doesn't get run and a warning explains that the token can't be imported.
+func TryVaultTokenFile() sdk.Importer {
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		configPath := in.Getenv("VAULT_CONFIG_PATH")
+		if configPath == "" {
+			configPath = defaultConfigPath
+		}
+
+		helper, err := tokenHelper(in, configPath)
+		if err != nil {
+			attempt := out.NewAttempt(importer.SourceFile(configPath))
+			attempt.AddError(err)
+			return
+		}
+
+		if helper != "" {
+			attempt := out.NewAttempt(importer.SourceFile(configPath))
+			attempt.AddWarning(fmt.Sprintf("The Vault CLI stores its token with the token helper %s, which doesn't get run, so the token can't be imported: run `vault print token` and save the token in 1Password yourself", helper))
+			return
+		}
+
+		importer.TryFile(defaultTokenPath, func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+			addTokenCandidate(in, out, contents.ToString())
+		})(ctx, in, out)
+	}
+}
+
+// tokenHelper returns the path of the token helper configured in the Vault CLI config file, if any.
+func tokenHelper(in sdk.ImportInput, configPath string) (string, error) {
+	contents, err := os.ReadFile(in.ResolvePath(configPath))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	match := tokenHelperPattern.FindSubmatch(contents)
+	if match == nil {
+		return "", nil
+	}
+
+	return string(match[1]), nil
+}
+
+func addTokenCandidate(in sdk.ImportInput, out *sdk.ImportAttempt, token string) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return
+	}
+
+	fields := map[sdk.FieldName]string{
+		fieldname.Token: token,
+	}
+
+	// The token file doesn't store which server the token is for, so use the address from the environment if it's set
+	if addr := in.Getenv("VAULT_ADDR"); addr != "" {
+		fields[fieldname.Address] = addr
+	}
+
+	out.AddCandidate(sdk.ImportCandidate{
+		Fields: fields,
+	})
+}
@@ -1,9 +1,16 @@
 package sdk
 
 type Diagnostics struct {
-	Errors []Error
+	Errors   []Error
+	Warnings []Warning
 }
 
 type Error struct {
 	Message string
 }
+
+// Warning reports a problem that doesn't cause the operation to fail, such as an occurrence of a
+// credential that was detected but could not be imported.
+type Warning struct {
+	Message string
+}
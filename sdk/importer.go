@@ -2,7 +2,9 @@ package sdk
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -40,6 +42,11 @@ type ImportSource struct {
 type ImportInput struct {
 	HomeDir string
 	RootDir string
+
+	// Environment contains the environment variables importers should look at. If it's nil, the environment of the
+	// current process is used. Importers should read environment variables through Getenv, so they can be run
+	// against an explicit environment, e.g. an empty one.
+	Environment map[string]string
 }
 
 type ImportOutput struct {
@@ -59,6 +66,13 @@ func (out *ImportOutput) Errors() (errors []Error) {
 	return
 }
 
+func (out *ImportOutput) Warnings() (warnings []Warning) {
+	for _, attempt := range out.Attempts {
+		warnings = append(warnings, attempt.Diagnostics.Warnings...)
+	}
+	return
+}
+
 func (out *ImportOutput) AllCandidates() (candidates []ImportCandidate) {
 	for _, attempt := range out.Attempts {
 		candidates = append(candidates, attempt.Candidates...)
@@ -82,6 +96,10 @@ func (out *ImportAttempt) AddError(err error) {
 	out.Diagnostics.Errors = append(out.Diagnostics.Errors, Error{err.Error()})
 }
 
+func (out *ImportAttempt) AddWarning(message string) {
+	out.Diagnostics.Warnings = append(out.Diagnostics.Warnings, Warning{message})
+}
+
 func (in *ImportInput) FromHomeDir(path ...string) string {
 	return filepath.Join(append([]string{in.HomeDir}, path...)...)
 }
@@ -89,3 +107,33 @@ func (in *ImportInput) FromHomeDir(path ...string) string {
 func (in *ImportInput) FromRootDir(path ...string) string {
 	return filepath.Join(append([]string{in.RootDir}, path...)...)
 }
+
+// Getenv returns the value of the environment variable from Environment, or from the environment of the current
+// process if Environment is nil.
+func (in *ImportInput) Getenv(name string) string {
+	if in.Environment == nil {
+		return os.Getenv(name)
+	}
+	return in.Environment[name]
+}
+
+// ResolvePath returns where to find a path as it's written in config files and environment variables: paths
+// starting with "~/" are relative to the home dir, all other paths are relative to the root dir. Paths that already
+// point into the root dir, e.g. to a file a provisioner wrote there, are returned as they are.
+func (in *ImportInput) ResolvePath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return in.FromHomeDir(strings.TrimPrefix(path, "~"))
+	}
+	if in.isInRootDir(path) {
+		return path
+	}
+	return in.FromRootDir(path)
+}
+
+func (in *ImportInput) isInRootDir(path string) bool {
+	if in.RootDir == "" || !filepath.IsAbs(path) {
+		return false
+	}
+	rel, err := filepath.Rel(in.RootDir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+// TryConnectionURIEnvVar looks for a connection URI, such as DATABASE_URL, in the specified environment variable
+// and adds an import candidate with the Host, Port, User, Password and Database fields parsed from it. Only URIs
+// with one of the specified schemes are imported.
+func TryConnectionURIEnvVar(envVarName string, schemes ...string) sdk.Importer {
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		attempt := out.NewAttempt(SourceEnvVars(envVarName))
+
+		value := in.Getenv(envVarName)
+		if value == "" {
+			return
+		}
+
+		uri, err := url.Parse(value)
+		if err != nil {
+			attempt.AddError(fmt.Errorf("parsing %s: invalid connection URI", envVarName))
+			return
+		}
+
+		if !hasScheme(uri, schemes) {
+			return
+		}
+
+		fields := ConnectionURIFields(uri)
+		if len(fields) == 0 {
+			return
+		}
+
+		attempt.AddCandidate(sdk.ImportCandidate{
+			Fields:   fields,
+			NameHint: SanitizeNameHint(fields[fieldname.Database]),
+		})
+	}
+}
+
+// ConnectionURIFields extracts the Host, Port, User, Password and Database fields from a connection URI.
+// Percent-encoded values get decoded. Fields that are not present in the URI are omitted.
+// Query parameters, such as "sslmode", have no corresponding field and are not imported. To provision them again,
+// pass them to the provisioner with provision.URIParam.
+func ConnectionURIFields(uri *url.URL) map[sdk.FieldName]string {
+	fields := make(map[sdk.FieldName]string)
+
+	if host := uri.Hostname(); host != "" {
+		fields[fieldname.Host] = host
+	}
+
+	if port := uri.Port(); port != "" {
+		fields[fieldname.Port] = port
+	}
+
+	if uri.User != nil {
+		if user := uri.User.Username(); user != "" {
+			fields[fieldname.User] = user
+		}
+		if password, ok := uri.User.Password(); ok && password != "" {
+			fields[fieldname.Password] = password
+		}
+	}
+
+	if database := strings.TrimPrefix(uri.Path, "/"); database != "" {
+		fields[fieldname.Database] = database
+	}
+
+	return fields
+}
+
+func hasScheme(uri *url.URL, schemes []string) bool {
+	if len(schemes) == 0 {
+		return true
+	}
+
+	for _, scheme := range schemes {
+		if strings.EqualFold(uri.Scheme, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
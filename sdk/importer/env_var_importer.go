@@ -2,7 +2,6 @@ package importer
 
 import (
 	"context"
-	"os"
 
 	"github.com/1Password/shell-plugins/sdk"
 )
@@ -14,7 +13,7 @@ func TryAllEnvVars(fieldName sdk.FieldName, possibleEnvVarNames ...string) sdk.I
 		for _, envVarName := range possibleEnvVarNames {
 			attempt := out.NewAttempt(SourceEnvVars(envVarName))
 
-			if value := os.Getenv(envVarName); value != "" {
+			if value := in.Getenv(envVarName); value != "" {
 				attempt.AddCandidate(sdk.ImportCandidate{
 					Fields: map[sdk.FieldName]string{
 						fieldName: value,
@@ -33,7 +32,7 @@ func TryEnvVarPair(pairPossibilities map[string]sdk.FieldName) sdk.Importer {
 		candidateFields := make(map[sdk.FieldName]string)
 
 		for possibleEnvVarName, fieldName := range pairPossibilities {
-			if value := os.Getenv(possibleEnvVarName); value != "" {
+			if value := in.Getenv(possibleEnvVarName); value != "" {
 				candidateFields[fieldName] = value
 			}
 
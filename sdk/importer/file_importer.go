@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/1Password/shell-plugins/sdk"
 	"github.com/BurntSushi/toml"
@@ -16,15 +14,8 @@ import (
 
 func TryFile(path string, result func(ctx context.Context, contents FileContents, in sdk.ImportInput, out *sdk.ImportAttempt)) sdk.Importer {
 	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
-		abspath := path
-		if strings.HasPrefix(path, "~/") {
-			abspath = filepath.Join(in.HomeDir, strings.TrimPrefix(path, "~/"))
-		} else if strings.HasPrefix(path, "/") {
-			abspath = filepath.Join(in.RootDir, path)
-		}
-
 		attempt := out.NewAttempt(SourceFile(path))
-		contents, err := os.ReadFile(abspath)
+		contents, err := os.ReadFile(in.ResolvePath(path))
 		if os.IsNotExist(err) {
 			return
 		} else if err != nil {
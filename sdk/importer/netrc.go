@@ -0,0 +1,267 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/1Password/shell-plugins/sdk"
+)
+
+// The tokens of a netrc entry that can be mapped to fields.
+const (
+	NetrcMachine  = "machine"
+	NetrcLogin    = "login"
+	NetrcPassword = "password"
+	NetrcAccount  = "account"
+)
+
+// NetrcEntry is a single entry in a netrc file, which is either a machine entry or the default entry.
+type NetrcEntry struct {
+	Machine  string
+	Default  bool
+	Login    string
+	Password string
+	Account  string
+}
+
+// value returns the value of the specified token.
+func (e NetrcEntry) value(token string) string {
+	switch token {
+	case NetrcMachine:
+		return e.Machine
+	case NetrcLogin:
+		return e.Login
+	case NetrcPassword:
+		return e.Password
+	case NetrcAccount:
+		return e.Account
+	}
+	return ""
+}
+
+// NetrcPath returns the path of the netrc file: the path in the NETRC environment variable if it's set, or
+// ~/.netrc otherwise.
+func NetrcPath(in sdk.ImportInput) string {
+	if path := in.Getenv("NETRC"); path != "" {
+		return path
+	}
+	return "~/.netrc"
+}
+
+// TryNetrcFile looks for entries for the specified machines in the netrc file and adds an import candidate for each
+// of them. The mapping specifies which token of the entry (e.g. NetrcPassword) goes into which field. Entries that
+// don't have all mapped tokens set are skipped. The default entry is only imported if no machines are specified.
+func TryNetrcFile(mapping map[string]sdk.FieldName, machines ...string) sdk.Importer {
+	return func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		TryFile(NetrcPath(in), func(ctx context.Context, contents FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+			entries, err := ParseNetrc(contents.ToString())
+			if err != nil {
+				out.AddError(err)
+				return
+			}
+
+			for _, entry := range entries {
+				if !netrcEntryMatches(entry, machines) {
+					continue
+				}
+
+				fields := make(map[sdk.FieldName]string)
+				for token, fieldName := range mapping {
+					if value := entry.value(token); value != "" {
+						fields[fieldName] = value
+					}
+				}
+				if len(fields) == 0 || len(fields) < len(mapping) {
+					continue
+				}
+
+				out.AddCandidate(sdk.ImportCandidate{
+					Fields:   fields,
+					NameHint: SanitizeNameHint(entry.Login),
+				})
+			}
+		})(ctx, in, out)
+	}
+}
+
+func netrcEntryMatches(entry NetrcEntry, machines []string) bool {
+	if len(machines) == 0 {
+		return true
+	}
+	if entry.Default {
+		return false
+	}
+	for _, machine := range machines {
+		if strings.EqualFold(entry.Machine, machine) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNetrc parses the contents of a netrc file. Tokens can be separated by any whitespace, including tabs and
+// newlines, so multiple tokens can be on the same line. Values containing whitespace can be double-quoted, with
+// backslash escapes. Comments (starting with #) and macro definitions (macdef) are skipped.
+func ParseNetrc(contents string) ([]NetrcEntry, error) {
+	s := &netrcScanner{input: []rune(contents)}
+
+	var entries []NetrcEntry
+	var current *NetrcEntry
+	for {
+		token, ok, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		switch token {
+		case "machine":
+			machine, err := s.value(token)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, NetrcEntry{Machine: machine})
+			current = &entries[len(entries)-1]
+		case "default":
+			entries = append(entries, NetrcEntry{Default: true})
+			current = &entries[len(entries)-1]
+		case "login", "password", "account":
+			value, err := s.value(token)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil {
+				return nil, fmt.Errorf("netrc: '%s' on line %d is not part of a machine or default entry", token, s.line)
+			}
+			switch token {
+			case "login":
+				current.Login = value
+			case "password":
+				current.Password = value
+			case "account":
+				current.Account = value
+			}
+		case "macdef":
+			if _, err := s.value(token); err != nil {
+				return nil, err
+			}
+			s.skipMacro()
+		default:
+			// Ignore unknown tokens, like other netrc parsers do
+		}
+	}
+
+	return entries, nil
+}
+
+type netrcScanner struct {
+	input []rune
+	pos   int
+	line  int
+}
+
+// value returns the token following the specified keyword.
+func (s *netrcScanner) value(keyword string) (string, error) {
+	value, ok, err := s.next()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("netrc: missing value for '%s' on line %d", keyword, s.line)
+	}
+	return value, nil
+}
+
+// next returns the next token, skipping whitespace and comments.
+func (s *netrcScanner) next() (string, bool, error) {
+	if s.line == 0 {
+		s.line = 1
+	}
+
+	for s.pos < len(s.input) {
+		r := s.input[s.pos]
+		if r == '\n' {
+			s.line++
+		}
+		if unicode.IsSpace(r) {
+			s.pos++
+			continue
+		}
+		if r == '#' {
+			for s.pos < len(s.input) && s.input[s.pos] != '\n' {
+				s.pos++
+			}
+			continue
+		}
+		break
+	}
+
+	if s.pos >= len(s.input) {
+		return "", false, nil
+	}
+
+	var token strings.Builder
+	if s.input[s.pos] == '"' {
+		start := s.line
+		s.pos++
+		for {
+			if s.pos >= len(s.input) {
+				return "", false, fmt.Errorf("netrc: unterminated quoted value on line %d", start)
+			}
+			r := s.input[s.pos]
+			s.pos++
+			if r == '"' {
+				return token.String(), true, nil
+			}
+			if r == '\\' && s.pos < len(s.input) {
+				r = s.input[s.pos]
+				s.pos++
+			}
+			if r == '\n' {
+				s.line++
+			}
+			token.WriteRune(r)
+		}
+	}
+
+	for s.pos < len(s.input) && !unicode.IsSpace(s.input[s.pos]) {
+		r := s.input[s.pos]
+		s.pos++
+		if r == '\\' && s.pos < len(s.input) {
+			r = s.input[s.pos]
+			s.pos++
+		}
+		token.WriteRune(r)
+	}
+
+	return token.String(), true, nil
+}
+
+// skipMacro skips the body of a macro definition, which ends at the first empty line.
+func (s *netrcScanner) skipMacro() {
+	// The body starts on the line after the macdef line
+	for s.pos < len(s.input) && s.input[s.pos] != '\n' {
+		s.pos++
+	}
+
+	for s.pos < len(s.input) {
+		// s.pos is at the newline that ends the previous line
+		s.pos++
+		s.line++
+
+		lineEnd := s.pos
+		for lineEnd < len(s.input) && s.input[lineEnd] != '\n' {
+			lineEnd++
+		}
+
+		if strings.TrimSpace(string(s.input[s.pos:lineEnd])) == "" {
+			s.pos = lineEnd
+			return
+		}
+		s.pos = lineEnd
+	}
+}
@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetrc(t *testing.T) {
+	contents := "# personal machines\n" +
+		"machine api.heroku.com login wendy@appleseed.com password dh7k7m662pqglxaybr1p0gpg1cu33example\n" +
+		"machine\tgit.heroku.com\n\tlogin\twendy@appleseed.com\n\tpassword\tdh7k7m662pqglxaybr1p0gpg1cu33example\n" +
+		"\n" +
+		"macdef init\n" +
+		"cd /pub\n" +
+		"machine not.a.machine login nobody password nothing\n" +
+		"\n" +
+		"machine example.com login \"wendy appleseed\" password \"p@ss \\\"word\\\"\" account acme\n" +
+		"default login anonymous password wendy@appleseed.com\n"
+
+	entries, err := ParseNetrc(contents)
+	require.NoError(t, err)
+
+	assert.Equal(t, []NetrcEntry{
+		{Machine: "api.heroku.com", Login: "wendy@appleseed.com", Password: "dh7k7m662pqglxaybr1p0gpg1cu33example"},
+		{Machine: "git.heroku.com", Login: "wendy@appleseed.com", Password: "dh7k7m662pqglxaybr1p0gpg1cu33example"},
+		{Machine: "example.com", Login: "wendy appleseed", Password: `p@ss "word"`, Account: "acme"},
+		{Default: true, Login: "anonymous", Password: "wendy@appleseed.com"},
+	}, entries)
+}
+
+func TestParseNetrcErrors(t *testing.T) {
+	for contents, expected := range map[string]string{
+		"login wendy":                             "netrc: 'login' on line 1 is not part of a machine or default entry",
+		"machine example.com\nlogin":              "netrc: missing value for 'login' on line 2",
+		"machine example.com password \"unclosed": "netrc: unterminated quoted value on line 1",
+	} {
+		_, err := ParseNetrc(contents)
+		assert.EqualError(t, err, expected)
+	}
+}
@@ -0,0 +1,17 @@
+package sdk
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportInputResolvePath(t *testing.T) {
+	root := t.TempDir()
+	in := ImportInput{HomeDir: filepath.Join(root, "~"), RootDir: root}
+
+	assert.Equal(t, filepath.Join(root, "~", ".pgpass"), in.ResolvePath("~/.pgpass"))
+	assert.Equal(t, filepath.Join(root, "etc", "my.cnf"), in.ResolvePath("/etc/my.cnf"))
+	assert.Equal(t, filepath.Join(root, "tmp", "pgpass"), in.ResolvePath(filepath.Join(root, "tmp", "pgpass")), "paths in the root dir should not be joined onto it again")
+}
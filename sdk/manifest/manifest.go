@@ -0,0 +1,289 @@
+// Package manifest describes the plugin registry in a format that can be consumed without compiling Go, such as by
+// other tools and documentation sites.
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// SchemaVersion is the version of the manifest format. It's incremented whenever a field is removed or changes
+// meaning, so consumers can detect manifests they don't understand. Adding fields doesn't change the version.
+const SchemaVersion = 1
+
+// Format is a format the manifest can be written in.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Manifest describes all plugins in the registry.
+type Manifest struct {
+	SchemaVersion int      `json:"schemaVersion" yaml:"schemaVersion"`
+	Plugins       []Plugin `json:"plugins" yaml:"plugins"`
+}
+
+type Plugin struct {
+	Name        string       `json:"name" yaml:"name"`
+	Platform    Platform     `json:"platform" yaml:"platform"`
+	Credentials []Credential `json:"credentials" yaml:"credentials"`
+	Executables []Executable `json:"executables" yaml:"executables"`
+}
+
+type Platform struct {
+	Name     string `json:"name" yaml:"name"`
+	Homepage string `json:"homepage,omitempty" yaml:"homepage,omitempty"`
+}
+
+type Credential struct {
+	Name          string  `json:"name" yaml:"name"`
+	DocsURL       string  `json:"docsUrl,omitempty" yaml:"docsUrl,omitempty"`
+	ManagementURL string  `json:"managementUrl,omitempty" yaml:"managementUrl,omitempty"`
+	Fields        []Field `json:"fields" yaml:"fields"`
+
+	// The description of the default provisioner.
+	Provisioner string `json:"provisioner,omitempty" yaml:"provisioner,omitempty"`
+
+	// The locations the importer looks for existing credentials, if the credential has an importer.
+	Importer *Importer `json:"importer,omitempty" yaml:"importer,omitempty"`
+}
+
+type Field struct {
+	Name        string       `json:"name" yaml:"name"`
+	Description string       `json:"description" yaml:"description"`
+	Secret      bool         `json:"secret" yaml:"secret"`
+	Optional    bool         `json:"optional" yaml:"optional"`
+	Type        string       `json:"type" yaml:"type"`
+	Default     string       `json:"default,omitempty" yaml:"default,omitempty"`
+	Placeholder string       `json:"placeholder,omitempty" yaml:"placeholder,omitempty"`
+	Enum        []string     `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Aliases     []string     `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Composition *Composition `json:"composition,omitempty" yaml:"composition,omitempty"`
+}
+
+type Composition struct {
+	Length    int      `json:"length,omitempty" yaml:"length,omitempty"`
+	MinLength int      `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength int      `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Prefixes  []string `json:"prefixes,omitempty" yaml:"prefixes,omitempty"`
+	Charset   Charset  `json:"charset" yaml:"charset"`
+	Pattern   string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// Whether the value contains a checksum. The checksum algorithm itself is only available in Go.
+	Checksum bool `json:"checksum" yaml:"checksum"`
+}
+
+type Charset struct {
+	Uppercase bool   `json:"uppercase" yaml:"uppercase"`
+	Lowercase bool   `json:"lowercase" yaml:"lowercase"`
+	Digits    bool   `json:"digits" yaml:"digits"`
+	Symbols   bool   `json:"symbols" yaml:"symbols"`
+	Specific  string `json:"specific,omitempty" yaml:"specific,omitempty"`
+}
+
+type Importer struct {
+	EnvVars []string `json:"envVars" yaml:"envVars"`
+	Files   []string `json:"files" yaml:"files"`
+}
+
+type Executable struct {
+	Name    string            `json:"name" yaml:"name"`
+	Runs    []string          `json:"runs" yaml:"runs"`
+	DocsURL string            `json:"docsUrl,omitempty" yaml:"docsUrl,omitempty"`
+	Uses    []CredentialUsage `json:"uses" yaml:"uses"`
+}
+
+type CredentialUsage struct {
+	Plugin     string `json:"plugin" yaml:"plugin"`
+	Credential string `json:"credential" yaml:"credential"`
+
+	// The description of the provisioner the executable uses for the credential, if it overrides the default one.
+	Provisioner string `json:"provisioner,omitempty" yaml:"provisioner,omitempty"`
+
+	// Whether the executable can run without the credential.
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// New creates a manifest of the specified plugins. The importers get run against an empty directory and with an
+// empty environment to find out which environment variables and files they look at by default.
+func New(ctx context.Context, list []schema.Plugin) (Manifest, error) {
+	root, err := os.MkdirTemp("", "shell-plugins-manifest-")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer os.RemoveAll(root)
+
+	m := Manifest{SchemaVersion: SchemaVersion, Plugins: []Plugin{}}
+	for _, p := range list {
+		plugin := Plugin{
+			Name:        p.Name,
+			Platform:    Platform{Name: p.Platform.Name, Homepage: urlString(p.Platform.Homepage)},
+			Credentials: []Credential{},
+			Executables: []Executable{},
+		}
+
+		for _, cred := range p.Credentials {
+			plugin.Credentials = append(plugin.Credentials, newCredential(ctx, cred, root))
+		}
+
+		for _, exe := range p.Executables {
+			plugin.Executables = append(plugin.Executables, newExecutable(p, exe))
+		}
+
+		m.Plugins = append(m.Plugins, plugin)
+	}
+	return m, nil
+}
+
+func newCredential(ctx context.Context, cred schema.CredentialType, root string) Credential {
+	credential := Credential{
+		Name:          cred.Name.String(),
+		DocsURL:       urlString(cred.DocsURL),
+		ManagementURL: urlString(cred.ManagementURL),
+		Fields:        []Field{},
+	}
+
+	if cred.DefaultProvisioner != nil {
+		credential.Provisioner = cred.DefaultProvisioner.Description()
+	}
+
+	if cred.Importer != nil {
+		credential.Importer = importerSources(ctx, cred.Importer, root)
+	}
+
+	for _, f := range cred.Fields {
+		field := Field{
+			Name:        f.Name.String(),
+			Description: f.MarkdownDescription,
+			Secret:      f.Secret,
+			Optional:    f.Optional,
+			Type:        string(f.Type),
+			Default:     f.Default,
+			Placeholder: f.Placeholder,
+			Enum:        f.Enum,
+		}
+		if field.Type == "" {
+			field.Type = string(schema.FieldTypeString)
+		}
+		for _, alias := range f.Aliases {
+			field.Aliases = append(field.Aliases, alias.String())
+		}
+		if comp := f.Composition; comp != nil {
+			field.Composition = &Composition{
+				Length:    comp.Length,
+				MinLength: comp.MinLength,
+				MaxLength: comp.MaxLength,
+				Prefixes:  comp.AllowedPrefixes(),
+				Charset: Charset{
+					Uppercase: comp.Charset.Uppercase,
+					Lowercase: comp.Charset.Lowercase,
+					Digits:    comp.Charset.Digits,
+					Symbols:   comp.Charset.Symbols,
+					Specific:  string(comp.Charset.Specific),
+				},
+				Pattern:  comp.Pattern,
+				Checksum: comp.Checksum != nil,
+			}
+		}
+		credential.Fields = append(credential.Fields, field)
+	}
+
+	return credential
+}
+
+// importerSources runs the importer and collects the sources of all attempts it makes. Importers add an attempt for
+// every location they look at, whether or not they find a credential there. The importer gets an empty environment,
+// so that importers that take paths from the environment, like AWS_SHARED_CREDENTIALS_FILE, report their default
+// paths.
+func importerSources(ctx context.Context, importer sdk.Importer, root string) *Importer {
+	out := sdk.ImportOutput{}
+	importer(ctx, sdk.ImportInput{HomeDir: root, RootDir: root, Environment: map[string]string{}}, &out)
+
+	sources := &Importer{EnvVars: []string{}, Files: []string{}}
+	for _, attempt := range out.Attempts {
+		sources.EnvVars = appendUnique(sources.EnvVars, attempt.Source.Env...)
+		sources.Files = appendUnique(sources.Files, attempt.Source.Files...)
+	}
+
+	// Some importers iterate over maps, so sort to keep the manifest the same across runs
+	sort.Strings(sources.EnvVars)
+	sort.Strings(sources.Files)
+	return sources
+}
+
+func newExecutable(p schema.Plugin, exe schema.Executable) Executable {
+	executable := Executable{
+		Name:    exe.Name,
+		Runs:    exe.Runs,
+		DocsURL: urlString(exe.DocsURL),
+		Uses:    []CredentialUsage{},
+	}
+
+	for _, usage := range exe.Uses {
+		pluginName := usage.Plugin
+		if pluginName == "" {
+			pluginName = p.Name
+		}
+
+		credentialUsage := CredentialUsage{Plugin: pluginName, Credential: usage.Name.String(), Optional: usage.Optional}
+		if usage.Provisioner != nil {
+			credentialUsage.Provisioner = usage.Provisioner.Description()
+		}
+		executable.Uses = append(executable.Uses, credentialUsage)
+	}
+
+	return executable
+}
+
+// Write writes the manifest in the specified format.
+func (m Manifest) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON, "":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	case FormatYAML:
+		contents, err := yaml.Marshal(m)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(contents)
+		return err
+	default:
+		return fmt.Errorf("unknown manifest format '%s', expected one of: json, yaml", format)
+	}
+}
+
+func urlString(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, item := range list {
+			if item == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
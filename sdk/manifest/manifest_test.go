@@ -0,0 +1,177 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/provision"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func examplePlugin() schema.Plugin {
+	return schema.Plugin{
+		Name: "example",
+		Platform: schema.PlatformInfo{
+			Name:     "Example",
+			Homepage: sdk.URL("https://example.com"),
+		},
+		Credentials: []schema.CredentialType{
+			{
+				Name:    "API Key",
+				DocsURL: sdk.URL("https://example.com/docs/api-keys"),
+				Fields: []schema.CredentialField{
+					{
+						Name:                fieldname.APIKey,
+						MarkdownDescription: "API key used to authenticate to Example.",
+						Secret:              true,
+						Composition: &schema.ValueComposition{
+							Length: 20,
+							Prefix: "ex_",
+							Charset: schema.Charset{
+								Lowercase: true,
+								Digits:    true,
+								Specific:  []rune{'-'},
+							},
+						},
+					},
+					{
+						Name:                fieldname.Region,
+						MarkdownDescription: "Region to use.",
+						Optional:            true,
+						Enum:                []string{"eu", "us"},
+						Aliases:             []sdk.FieldName{"Zone"},
+					},
+				},
+				DefaultProvisioner: provision.EnvVars(map[string]sdk.FieldName{"EXAMPLE_API_KEY": fieldname.APIKey}),
+				Importer: importer.TryAll(
+					importer.TryEnvVarPair(map[string]sdk.FieldName{
+						"EXAMPLE_REGION":  fieldname.Region,
+						"EXAMPLE_API_KEY": fieldname.APIKey,
+					}),
+					importer.TryFile("~/.example/config", func(ctx context.Context, contents importer.FileContents, in sdk.ImportInput, out *sdk.ImportAttempt) {
+						// Only the source of the attempt ends up in the manifest
+					}),
+				),
+			},
+		},
+		Executables: []schema.Executable{
+			{
+				Name:    "Example CLI",
+				Runs:    []string{"example"},
+				DocsURL: sdk.URL("https://example.com/docs/cli"),
+				Uses: []schema.CredentialUsage{
+					{Name: "API Key"},
+					{Name: "Personal Access Token", Plugin: "other", Provisioner: provision.EnvVars(map[string]sdk.FieldName{"OTHER_TOKEN": fieldname.Token})},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("EXAMPLE_API_KEY", "ex_abcdefghijklmnopq")
+
+	m, err := New(context.Background(), []schema.Plugin{examplePlugin()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ex_abcdefghijklmnopq", os.Getenv("EXAMPLE_API_KEY"), "the environment should not be changed")
+
+	assert.Equal(t, Manifest{
+		SchemaVersion: SchemaVersion,
+		Plugins: []Plugin{
+			{
+				Name:     "example",
+				Platform: Platform{Name: "Example", Homepage: "https://example.com"},
+				Credentials: []Credential{
+					{
+						Name:    "API Key",
+						DocsURL: "https://example.com/docs/api-keys",
+						Fields: []Field{
+							{
+								Name:        "API Key",
+								Description: "API key used to authenticate to Example.",
+								Secret:      true,
+								Type:        "string",
+								Composition: &Composition{
+									Length:   20,
+									Prefixes: []string{"ex_"},
+									Charset:  Charset{Lowercase: true, Digits: true, Specific: "-"},
+								},
+							},
+							{
+								Name:        "Region",
+								Description: "Region to use.",
+								Optional:    true,
+								Type:        "string",
+								Enum:        []string{"eu", "us"},
+								Aliases:     []string{"Zone"},
+							},
+						},
+						Provisioner: "Provision environment variables: EXAMPLE_API_KEY",
+						Importer: &Importer{
+							EnvVars: []string{"EXAMPLE_API_KEY", "EXAMPLE_REGION"},
+							Files:   []string{"~/.example/config"},
+						},
+					},
+				},
+				Executables: []Executable{
+					{
+						Name:    "Example CLI",
+						Runs:    []string{"example"},
+						DocsURL: "https://example.com/docs/cli",
+						Uses: []CredentialUsage{
+							{Plugin: "example", Credential: "API Key"},
+							{Plugin: "other", Credential: "Personal Access Token", Provisioner: "Provision environment variables: OTHER_TOKEN"},
+						},
+					},
+				},
+			},
+		},
+	}, m)
+}
+
+func TestNewDefaultPaths(t *testing.T) {
+	t.Setenv("EXAMPLE_CONFIG_FILE", "/custom/config")
+
+	p := examplePlugin()
+	p.Credentials[0].Importer = func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+		path := in.Getenv("EXAMPLE_CONFIG_FILE")
+		if path == "" {
+			path = "~/.example/config"
+		}
+		out.NewAttempt(importer.SourceFile(path))
+	}
+
+	m, err := New(context.Background(), []schema.Plugin{p})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"~/.example/config"}, m.Plugins[0].Credentials[0].Importer.Files, "importers should not see the environment of the process")
+}
+
+func TestWrite(t *testing.T) {
+	m, err := New(context.Background(), []schema.Plugin{examplePlugin()})
+	require.NoError(t, err)
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, m.Write(&jsonOutput, FormatJSON))
+	var fromJSON Manifest
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &fromJSON))
+	assert.Equal(t, m, fromJSON)
+
+	var yamlOutput bytes.Buffer
+	require.NoError(t, m.Write(&yamlOutput, FormatYAML))
+	var fromYAML Manifest
+	require.NoError(t, yaml.Unmarshal(yamlOutput.Bytes(), &fromYAML))
+	assert.Equal(t, m, fromYAML)
+
+	assert.EqualError(t, m.Write(&bytes.Buffer{}, "toml"), "unknown manifest format 'toml', expected one of: json, yaml")
+}
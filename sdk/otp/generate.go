@@ -0,0 +1,88 @@
+package otp
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultMinValidity is how long a generated code should at least stay valid by default. Codes that expire sooner
+// could expire before the service receives them, so Generate waits for the next code instead.
+const DefaultMinValidity = 5 * time.Second
+
+// Option can be used to influence how codes are generated.
+type Option func(*generator)
+
+type generator struct {
+	now         func() time.Time
+	skew        time.Duration
+	minValidity time.Duration
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// WithClock sets the function to get the current time from. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(g *generator) {
+		g.now = now
+	}
+}
+
+// WithClockSkew corrects for a local clock that's off compared to the clock of the service, by generating the code
+// for the current time plus the specified offset. Use a positive offset if the local clock is behind.
+func WithClockSkew(offset time.Duration) Option {
+	return func(g *generator) {
+		g.skew = offset
+	}
+}
+
+// WithMinValidity sets how long a generated code should at least stay valid. Defaults to DefaultMinValidity. Use 0
+// to never wait for the next code.
+func WithMinValidity(d time.Duration) Option {
+	return func(g *generator) {
+		g.minValidity = d
+	}
+}
+
+// Generate returns the current code for a TOTP setup, which is either an otpauth:// URI or a base32 encoded secret.
+// If the value already is a code, for example because the host computed it, it's returned as is. If the current code
+// expires within the minimum validity, Generate waits for the next one, unless the context is done first.
+func Generate(ctx context.Context, value string, opts ...Option) (string, error) {
+	if IsCode(value) {
+		return value, nil
+	}
+
+	key, err := ParseKey(value)
+	if err != nil {
+		return "", err
+	}
+
+	g := generator{
+		now:         time.Now,
+		minValidity: DefaultMinValidity,
+		sleep:       sleepContext,
+	}
+	for _, opt := range opts {
+		opt(&g)
+	}
+
+	t := g.now().Add(g.skew)
+	if remaining := key.ValidUntil(t).Sub(t); remaining < g.minValidity {
+		if err := g.sleep(ctx, remaining); err != nil {
+			return "", err
+		}
+		t = t.Add(remaining)
+	}
+
+	return key.CodeAt(t), nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
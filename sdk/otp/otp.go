@@ -0,0 +1,227 @@
+// Package otp generates time-based one-time passwords (TOTP) as described in RFC 6238, so that plugins can
+// authenticate with MFA using the TOTP setup stored in the item instead of a code computed by the host.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm is the HMAC hash function used to generate codes.
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+const (
+	// DefaultDigits is the number of digits of a code if the TOTP setup doesn't specify it.
+	DefaultDigits = 6
+
+	// DefaultPeriod is how long a code is valid if the TOTP setup doesn't specify it.
+	DefaultPeriod = 30 * time.Second
+
+	// minSecretLength is the minimum length of a secret in bytes (80 bits), which is what most services use. This also
+	// ensures a secret is never mistaken for a code.
+	minSecretLength = 10
+)
+
+var codeRegex = regexp.MustCompile(`^[0-9]{6,8}$`)
+
+// Key is a TOTP setup: the shared secret and the parameters to generate codes with.
+type Key struct {
+	Secret    []byte
+	Algorithm Algorithm
+	Digits    int
+	Period    time.Duration
+
+	// (Optional) The service and account the key is for, as specified in the label of an otpauth:// URI.
+	Issuer  string
+	Account string
+}
+
+// IsCode reports whether the value is a one-time code of 6 to 8 digits, rather than a TOTP setup.
+func IsCode(value string) bool {
+	return codeRegex.MatchString(strings.TrimSpace(value))
+}
+
+// ParseKey parses a TOTP setup, which is either an otpauth://totp/ URI or a base32 encoded secret. Secrets may contain
+// spaces, dashes, lowercase letters and padding, as they're often shown that way to make them easier to type over.
+// The returned error never contains the secret.
+func ParseKey(value string) (Key, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToLower(value), "otpauth://") {
+		return parseURI(value)
+	}
+
+	secret, err := decodeSecret(value)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: DefaultDigits, Period: DefaultPeriod}, nil
+}
+
+// parseURI parses an otpauth:// URI in the format used by Google Authenticator, e.g.
+// otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example.
+func parseURI(value string) (Key, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return Key{}, errors.New("invalid otpauth:// URI")
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return Key{}, fmt.Errorf("unsupported OTP type '%s', only totp is supported", u.Host)
+	}
+
+	query := u.Query()
+	if query.Get("secret") == "" {
+		return Key{}, errors.New("otpauth:// URI has no secret")
+	}
+	secret, err := decodeSecret(query.Get("secret"))
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: DefaultDigits, Period: DefaultPeriod}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		key.Issuer = strings.TrimSpace(issuer)
+		key.Account = strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = Algorithm(strings.ToUpper(algorithm))
+		if key.Algorithm.new() == nil {
+			return Key{}, fmt.Errorf("unsupported algorithm '%s', expected SHA1, SHA256 or SHA512", algorithm)
+		}
+	}
+
+	if digits := query.Get("digits"); digits != "" {
+		key.Digits, err = strconv.Atoi(digits)
+		if err != nil || key.Digits < 6 || key.Digits > 8 {
+			return Key{}, fmt.Errorf("unsupported number of digits '%s', expected 6 to 8", digits)
+		}
+	}
+
+	if period := query.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil || seconds <= 0 {
+			return Key{}, fmt.Errorf("invalid period '%s', expected a positive number of seconds", period)
+		}
+		key.Period = time.Duration(seconds) * time.Second
+	}
+
+	return key, nil
+}
+
+func decodeSecret(value string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(value))
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return nil, errors.New("secret is not base32 encoded")
+	}
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("secret should be at least %d bits", minSecretLength*8)
+	}
+	return secret, nil
+}
+
+func (a Algorithm) new() func() hash.Hash {
+	switch a {
+	case AlgorithmSHA1, "":
+		return sha1.New
+	case AlgorithmSHA256:
+		return sha256.New
+	case AlgorithmSHA512:
+		return sha512.New
+	}
+	return nil
+}
+
+// CodeAt returns the code for the time step that t falls in.
+func (k Key) CodeAt(t time.Time) string {
+	return k.code(k.counter(t))
+}
+
+// ValidUntil returns when the code for the time step that t falls in expires.
+func (k Key) ValidUntil(t time.Time) time.Time {
+	return time.Unix(int64(k.counter(t)+1)*k.periodSeconds(), 0)
+}
+
+// Verify reports whether the code matches the code for the time step that t falls in, or for one of the window
+// time steps before or after it, to allow for clock skew between the generating and verifying side.
+func (k Key) Verify(code string, t time.Time, window int) bool {
+	counter := int64(k.counter(t))
+	for offset := -int64(window); offset <= int64(window); offset++ {
+		if counter+offset < 0 {
+			continue
+		}
+		if hmac.Equal([]byte(code), []byte(k.code(uint64(counter+offset)))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k Key) periodSeconds() int64 {
+	seconds := int64(k.Period / time.Second)
+	if seconds <= 0 {
+		return int64(DefaultPeriod / time.Second)
+	}
+	return seconds
+}
+
+func (k Key) counter(t time.Time) uint64 {
+	unix := t.Unix()
+	if unix < 0 {
+		return 0
+	}
+	return uint64(unix / k.periodSeconds())
+}
+
+// code computes the HOTP value for the counter, as described in RFC 4226.
+func (k Key) code(counter uint64) string {
+	newHash := k.Algorithm.new()
+	if newHash == nil {
+		newHash = sha1.New
+	}
+	digits := k.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+	mac := hmac.New(newHash, k.Secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation: the last 4 bits of the HMAC determine where to take 31 bits from
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
@@ -0,0 +1,175 @@
+package otp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test vectors from appendix B of RFC 6238.
+func TestCodeAtRFC6238(t *testing.T) {
+	keys := map[Algorithm]Key{
+		AlgorithmSHA1:   {Secret: []byte("12345678901234567890"), Algorithm: AlgorithmSHA1, Digits: 8, Period: 30 * time.Second},
+		AlgorithmSHA256: {Secret: []byte("12345678901234567890123456789012"), Algorithm: AlgorithmSHA256, Digits: 8, Period: 30 * time.Second},
+		AlgorithmSHA512: {Secret: []byte("1234567890123456789012345678901234567890123456789012345678901234"), Algorithm: AlgorithmSHA512, Digits: 8, Period: 30 * time.Second},
+	}
+
+	cases := []struct {
+		unix     int64
+		expected map[Algorithm]string
+	}{
+		{59, map[Algorithm]string{AlgorithmSHA1: "94287082", AlgorithmSHA256: "46119246", AlgorithmSHA512: "90693936"}},
+		{1111111109, map[Algorithm]string{AlgorithmSHA1: "07081804", AlgorithmSHA256: "68084774", AlgorithmSHA512: "25091201"}},
+		{1111111111, map[Algorithm]string{AlgorithmSHA1: "14050471", AlgorithmSHA256: "67062674", AlgorithmSHA512: "99943326"}},
+		{1234567890, map[Algorithm]string{AlgorithmSHA1: "89005924", AlgorithmSHA256: "91819424", AlgorithmSHA512: "93441116"}},
+		{2000000000, map[Algorithm]string{AlgorithmSHA1: "69279037", AlgorithmSHA256: "90698825", AlgorithmSHA512: "38618901"}},
+		{20000000000, map[Algorithm]string{AlgorithmSHA1: "65353130", AlgorithmSHA256: "77737706", AlgorithmSHA512: "47863826"}},
+	}
+
+	for _, tc := range cases {
+		for algorithm, expected := range tc.expected {
+			assert.Equal(t, expected, keys[algorithm].CodeAt(time.Unix(tc.unix, 0)), "%s at %d", algorithm, tc.unix)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected Key
+		err      string
+	}{
+		"base32 secret": {
+			value:    "JBSWY3DPEHPK3PXP",
+			expected: Key{Secret: []byte("Hello!\xde\xad\xbe\xef"), Algorithm: AlgorithmSHA1, Digits: 6, Period: 30 * time.Second},
+		},
+		"base32 secret with spaces and lowercase letters": {
+			value:    "jbsw y3dp ehpk 3pxp",
+			expected: Key{Secret: []byte("Hello!\xde\xad\xbe\xef"), Algorithm: AlgorithmSHA1, Digits: 6, Period: 30 * time.Second},
+		},
+		"otpauth URI": {
+			value: "otpauth://totp/ACME%20Co:john.doe@email.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			expected: Key{
+				Secret:    []byte("Hello!\xde\xad\xbe\xef"),
+				Algorithm: AlgorithmSHA256,
+				Digits:    8,
+				Period:    60 * time.Second,
+				Issuer:    "ACME Co",
+				Account:   "john.doe@email.com",
+			},
+		},
+		"otpauth URI with defaults": {
+			value: "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP",
+			expected: Key{
+				Secret:    []byte("Hello!\xde\xad\xbe\xef"),
+				Algorithm: AlgorithmSHA1,
+				Digits:    6,
+				Period:    30 * time.Second,
+				Account:   "alice",
+			},
+		},
+		"HOTP URI": {
+			value: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=0",
+			err:   "unsupported OTP type 'hotp', only totp is supported",
+		},
+		"URI without secret": {
+			value: "otpauth://totp/alice",
+			err:   "otpauth:// URI has no secret",
+		},
+		"unsupported algorithm": {
+			value: "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+			err:   "unsupported algorithm 'MD5', expected SHA1, SHA256 or SHA512",
+		},
+		"invalid base32": {
+			value: "not base32 at all!",
+			err:   "secret is not base32 encoded",
+		},
+		"secret too short": {
+			value: "JBSWY3DP",
+			err:   "secret should be at least 80 bits",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := ParseKey(tc.value)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, key)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, err := ParseKey("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	previous := key.CodeAt(now.Add(-30 * time.Second))
+
+	assert.True(t, key.Verify(key.CodeAt(now), now, 0))
+	assert.False(t, key.Verify(previous, now, 0))
+	assert.True(t, key.Verify(previous, now, 1))
+}
+
+func TestGenerate(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	key, err := ParseKey(secret)
+	require.NoError(t, err)
+
+	// 1700000000 is 20 seconds into a 30 second time step
+	now := time.Unix(1700000000, 0)
+	clock := WithClock(func() time.Time { return now })
+
+	t.Run("returns codes as is", func(t *testing.T) {
+		code, err := Generate(context.Background(), "123456")
+		require.NoError(t, err)
+		assert.Equal(t, "123456", code)
+	})
+
+	t.Run("generates the current code", func(t *testing.T) {
+		code, err := Generate(context.Background(), secret, clock)
+		require.NoError(t, err)
+		assert.Equal(t, key.CodeAt(now), code)
+	})
+
+	t.Run("applies clock skew", func(t *testing.T) {
+		code, err := Generate(context.Background(), secret, clock, WithClockSkew(-30*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, key.CodeAt(now.Add(-30*time.Second)), code)
+	})
+
+	t.Run("waits for the next code if the current one expires soon", func(t *testing.T) {
+		var slept time.Duration
+		waitFor := func(g *generator) {
+			g.sleep = func(ctx context.Context, d time.Duration) error {
+				slept = d
+				return nil
+			}
+		}
+
+		code, err := Generate(context.Background(), secret, clock, WithMinValidity(15*time.Second), waitFor)
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Second, slept)
+		assert.Equal(t, key.CodeAt(now.Add(10*time.Second)), code)
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Generate(ctx, secret, clock, WithMinValidity(15*time.Second))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("invalid setup", func(t *testing.T) {
+		_, err := Generate(context.Background(), "JBSWY3DP")
+		assert.EqualError(t, err, "secret should be at least 80 bits")
+	})
+}
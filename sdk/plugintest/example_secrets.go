@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"regexp/syntax"
 	"strings"
 	"time"
 
@@ -16,27 +17,144 @@ const (
 	digits              = "0123456789"
 	symbols             = "~!@#$%^&*()-_+={}[]\\|<,>.?/\"';:`"
 	secretExampleSuffix = "EXAMPLE"
+
+	// The length of example values for compositions without a fixed or minimum length.
+	defaultExampleLength = 32
+
+	// The maximum number of times an unbounded repetition in a pattern gets repeated.
+	maxPatternRepeat = 8
+
+	// The number of times to try to generate a value matching the composition. Only generated values for patterns
+	// that contradict the other properties of the composition can fail to match.
+	maxGenerateAttempts = 100
 )
 
 var seededRand = rand.New(
 	rand.NewSource(time.Now().UnixNano()))
 
+// ExampleSecretFromComposition generates a random value that matches the composition, which includes the prefix,
+// length, charset, pattern and checksum. Exits if no matching value can be generated, e.g. because the pattern
+// contradicts the charset.
 func ExampleSecretFromComposition(v schema.ValueComposition) string {
+	var err error
+	for i := 0; i < maxGenerateAttempts; i++ {
+		value := generateExample(v)
+		if v.Checksum != nil {
+			value = v.Checksum.AddChecksum(value)
+		}
+
+		field := schema.CredentialField{Name: "Example", Composition: &v}
+		if err = field.ValidateValue(value); err == nil {
+			return value
+		}
+	}
+
+	log.Fatalf("Error while generating secret: no value matches the composition after %d attempts: %v", maxGenerateAttempts, err)
+	return ""
+}
+
+// ExampleFieldValue returns an example value for the field: a generated value if the field has a value composition,
+// otherwise its default value, first enum value, placeholder or an example of its type, whichever is valid first.
+// Returns an empty string if none of those apply, e.g. for string fields without any metadata.
+func ExampleFieldValue(field schema.CredentialField) string {
+	if field.Composition != nil {
+		return ExampleSecretFromComposition(*field.Composition)
+	}
+
+	candidates := []string{field.Default}
+	if len(field.Enum) > 0 {
+		candidates = append(candidates, field.Enum[0])
+	}
+	candidates = append(candidates, field.Placeholder, field.Type.Example())
+	for _, candidate := range candidates {
+		if candidate != "" && field.ValidateValue(candidate) == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func generateExample(v schema.ValueComposition) string {
+	if v.Pattern != "" {
+		return generateFromPattern(v)
+	}
+
 	prefix := getPrefix(v)
-	suffix := getSuffix(v)
-	base := generateBase(v, v.Length-len(prefix)-len(suffix))
+	length := getLength(v)
+	suffix := getSuffix(v, length)
+	base := generateBase(v, length-len(prefix)-len(suffix))
+
+	// The checksum is at the end of the value, so put the suffix before the base to not have it overwritten
+	if v.Checksum != nil {
+		return prefix + suffix + base
+	}
 
 	return prefix + base + suffix
 }
 
+// generateFromPattern generates a value matching the pattern, with the length of the composition if it has a length
+// constraint. Patterns that don't produce one of the allowed prefixes themselves get the first one put in front.
+func generateFromPattern(v schema.ValueComposition) string {
+	length := -1
+	if v.Length > 0 || v.MinLength > 0 || v.MaxLength > 0 {
+		length = getLength(v)
+	}
+
+	value := mustStringFromPattern(v.Pattern, charsToUse(v.Charset), length)
+	prefixes := v.AllowedPrefixes()
+	if len(prefixes) == 0 || hasAnyPrefix(value, prefixes) {
+		return value
+	}
+
+	prefix := getPrefix(v)
+	if length > len(prefix) {
+		length -= len(prefix)
+	} else if length >= 0 {
+		length = 0
+	}
+	return prefix + mustStringFromPattern(v.Pattern, charsToUse(v.Charset), length)
+}
+
+func mustStringFromPattern(pattern string, anyChars string, length int) string {
+	value, err := stringFromPattern(pattern, anyChars, length)
+	if err != nil {
+		log.Fatalf("Error while generating secret: %v", err)
+	}
+	return value
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func getPrefix(v schema.ValueComposition) string {
-	if v.Prefix != "" {
-		return v.Prefix
+	if prefixes := v.AllowedPrefixes(); len(prefixes) > 0 {
+		return prefixes[0]
 	}
 
 	return ""
 }
 
+func getLength(v schema.ValueComposition) int {
+	if v.Length > 0 {
+		return v.Length
+	}
+
+	length := defaultExampleLength
+	if v.MinLength > length {
+		length = v.MinLength
+	}
+	if v.MaxLength > 0 && v.MaxLength < length {
+		length = v.MaxLength
+	}
+	return length
+}
+
 func generateBase(v schema.ValueComposition, baseLength int) string {
 	chars := charsToUse(v.Charset)
 	generatedStr, err := stringFromCharset(baseLength, chars)
@@ -48,10 +166,10 @@ func generateBase(v schema.ValueComposition, baseLength int) string {
 	return generatedStr
 }
 
-func getSuffix(v schema.ValueComposition) string {
+func getSuffix(v schema.ValueComposition, length int) string {
 	var suffix string
 
-	if v.Length > len(secretExampleSuffix) && (v.Charset.Uppercase || v.Charset.Lowercase) {
+	if length > len(secretExampleSuffix) && (v.Charset.Uppercase || v.Charset.Lowercase) {
 		suffix = secretExampleSuffix
 		if v.Charset.Lowercase && !v.Charset.Uppercase {
 			suffix = strings.ToLower(secretExampleSuffix)
@@ -100,3 +218,176 @@ func charsToUse(c schema.Charset) string {
 
 	return chars
 }
+
+// stringFromPattern generates a random string that matches the regular expression. Characters that can be anything
+// are taken from the specified characters. If length isn't negative, repetitions are repeated as often as needed to
+// get as close to that length as the pattern allows, instead of a random number of times.
+func stringFromPattern(pattern string, anyChars string, length int) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	re = re.Simplify()
+
+	g := patternGenerator{anyChars: anyChars, budget: -1}
+	if length >= 0 {
+		g.budget = length - minPatternLength(re)
+		if g.budget < 0 {
+			g.budget = 0
+		}
+	}
+
+	var sb strings.Builder
+	err = g.write(&sb, re)
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// patternGenerator writes random strings that match a regular expression.
+type patternGenerator struct {
+	anyChars string
+
+	// The number of characters that can still be added on top of the minimum length of the pattern by repeating
+	// repetitions more than their minimum number of times. Negative if repetitions are repeated a random number of times.
+	budget int
+}
+
+func (g *patternGenerator) write(sb *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return nil
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+		return nil
+	case syntax.OpCharClass:
+		sb.WriteRune(runeFromClass(re.Rune, g.anyChars))
+		return nil
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		if g.anyChars == "" {
+			return fmt.Errorf("invalid charset provided")
+		}
+		sb.WriteByte(g.anyChars[seededRand.Intn(len(g.anyChars))])
+		return nil
+	case syntax.OpCapture:
+		return g.write(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := g.write(sb, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	case syntax.OpAlternate:
+		return g.write(sb, re.Sub[seededRand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		for i := g.repeatCount(re); i > 0; i-- {
+			if err := g.write(sb, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported regular expression: %s", re)
+	}
+}
+
+// repeatCount returns how many times to repeat the repetition: a random number of times within its bounds, or as many
+// times as the remaining budget allows.
+func (g *patternGenerator) repeatCount(re *syntax.Regexp) int {
+	min, max := repeatBounds(re)
+	if g.budget < 0 {
+		return min + seededRand.Intn(max-min+1)
+	}
+
+	subLength := minPatternLength(re.Sub[0])
+	if subLength == 0 {
+		return min
+	}
+
+	extra := g.budget / subLength
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max < 0)
+	if !unbounded && extra > max-min {
+		extra = max - min
+	}
+	g.budget -= extra * subLength
+	return min + extra
+}
+
+// minPatternLength returns the length of the shortest string that matches the regular expression.
+func minPatternLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpPlus:
+		return minPatternLength(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * minPatternLength(re.Sub[0])
+	case syntax.OpConcat:
+		var length int
+		for _, sub := range re.Sub {
+			length += minPatternLength(sub)
+		}
+		return length
+	case syntax.OpAlternate:
+		length := -1
+		for _, sub := range re.Sub {
+			if subLength := minPatternLength(sub); length < 0 || subLength < length {
+				length = subLength
+			}
+		}
+		return length
+	}
+	return 0
+}
+
+func repeatBounds(re *syntax.Regexp) (int, int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, maxPatternRepeat
+	case syntax.OpPlus:
+		return 1, maxPatternRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	}
+
+	if re.Max < 0 {
+		return re.Min, re.Min + maxPatternRepeat
+	}
+	return re.Min, re.Max
+}
+
+// runeFromClass returns a random character from the character class, which consists of pairs of ranges. Characters
+// that are also in the specified characters are preferred, so the value matches the charset of the composition.
+func runeFromClass(ranges []rune, preferred string) rune {
+	var inClass []rune
+	for _, r := range preferred {
+		for i := 0; i < len(ranges); i += 2 {
+			if r >= ranges[i] && r <= ranges[i+1] {
+				inClass = append(inClass, r)
+				break
+			}
+		}
+	}
+	if len(inClass) > 0 {
+		return inClass[seededRand.Intn(len(inClass))]
+	}
+
+	var size int
+	for i := 0; i < len(ranges); i += 2 {
+		size += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := seededRand.Intn(size)
+	for i := 0; i < len(ranges); i += 2 {
+		rangeSize := int(ranges[i+1]-ranges[i]) + 1
+		if n < rangeSize {
+			return ranges[i] + rune(n)
+		}
+		n -= rangeSize
+	}
+	return ranges[0]
+}
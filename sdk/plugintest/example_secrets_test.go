@@ -110,6 +110,131 @@ func TestSecretWithExpectedLength(t *testing.T) {
 	assert.Equal(t, expectedLength, len(result), fmt.Sprintf("should have %d chars length", expectedLength))
 }
 
+func TestSecretWithVariableLength(t *testing.T) {
+	cases := map[string]struct {
+		minLength      int
+		maxLength      int
+		expectedLength int
+	}{
+		"when only a maximum length is set": {
+			maxLength:      20,
+			expectedLength: 20,
+		},
+		"when the minimum length is longer than the default length": {
+			minLength:      40,
+			maxLength:      93,
+			expectedLength: 40,
+		},
+		"when no length is set": {
+			expectedLength: defaultExampleLength,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := schema.ValueComposition{
+				MinLength: tc.minLength,
+				MaxLength: tc.maxLength,
+				Charset:   schema.Charset{Uppercase: true},
+			}
+			result := ExampleSecretFromComposition(v)
+
+			assert.Equal(t, tc.expectedLength, len(result))
+		})
+	}
+}
+
+func TestSecretContainsFirstOfMultiplePrefixes(t *testing.T) {
+	v := schema.ValueComposition{
+		MinLength: 20,
+		Prefixes:  []string{"sk_", "rk_"},
+		Charset:   schema.Charset{Lowercase: true},
+	}
+	result := ExampleSecretFromComposition(v)
+
+	assert.True(t, strings.HasPrefix(result, "sk_"), "should contain sk_ prefix")
+}
+
+func TestSecretMatchesPattern(t *testing.T) {
+	pattern := `acct-[0-9]{4}_(live|test)_[A-Za-z0-9]{20,}`
+	v := schema.ValueComposition{
+		Pattern: pattern,
+		Charset: schema.Charset{Uppercase: true, Lowercase: true, Digits: true, Specific: []rune{'-', '_'}},
+	}
+
+	for i := 0; i < 20; i++ {
+		result := ExampleSecretFromComposition(v)
+		assert.Regexp(t, "^(?:"+pattern+")$", result)
+	}
+}
+
+func TestSecretMatchesPatternAndLength(t *testing.T) {
+	pattern := `[A-Z0-9-]+_[A-Z0-9]{32,}`
+	v := schema.ValueComposition{
+		Pattern: pattern,
+		Length:  56,
+		Charset: schema.Charset{Uppercase: true, Digits: true, Specific: []rune{'-', '_'}},
+	}
+
+	for i := 0; i < 20; i++ {
+		result := ExampleSecretFromComposition(v)
+		assert.Regexp(t, "^(?:"+pattern+")$", result)
+		assert.Len(t, result, 56)
+	}
+}
+
+func TestSecretMatchesPatternAndPrefix(t *testing.T) {
+	pattern := `[a-z_]+`
+	v := schema.ValueComposition{
+		Pattern:   pattern,
+		Prefix:    "key_",
+		MinLength: 20,
+		Charset:   schema.Charset{Lowercase: true, Specific: []rune{'_'}},
+	}
+
+	for i := 0; i < 20; i++ {
+		result := ExampleSecretFromComposition(v)
+		assert.True(t, strings.HasPrefix(result, "key_"), "should contain key_ prefix")
+		assert.Regexp(t, "^(?:"+pattern+")$", result)
+		assert.GreaterOrEqual(t, len(result), 20)
+	}
+}
+
+type sumChecksum struct{}
+
+func (sumChecksum) ValidateChecksum(value string) error {
+	if value[len(value)-1:] != digitSum(value) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
+
+func (sumChecksum) AddChecksum(value string) string {
+	return value[:len(value)-1] + digitSum(value)
+}
+
+// digitSum returns the last digit of the sum of all characters before the last character.
+func digitSum(value string) string {
+	var sum int
+	for _, r := range value[:len(value)-1] {
+		sum += int(r)
+	}
+	return fmt.Sprint(sum % 10)
+}
+
+func TestSecretHasValidChecksum(t *testing.T) {
+	v := schema.ValueComposition{
+		Length:   20,
+		Prefix:   "tok_",
+		Checksum: sumChecksum{},
+		Charset:  schema.Charset{Lowercase: true, Digits: true},
+	}
+	result := ExampleSecretFromComposition(v)
+
+	assert.NoError(t, sumChecksum{}.ValidateChecksum(result))
+	assert.True(t, strings.HasPrefix(result, "tok_"+strings.ToLower(secretExampleSuffix)), "should contain the suffix after the prefix")
+}
+
 func TestStingFromCharsetReturnErrorWhenNoCharsetProvided(t *testing.T) {
 	_, err := stringFromCharset(10, "")
 	if err == nil {
@@ -163,3 +288,11 @@ func containsOnlyLowercaseLetters(str string) (bool, error) {
 func containsOnlyDigits(str string) (bool, error) {
 	return regexp.Match("^[0-9]+$", []byte(str))
 }
+
+func TestExampleFieldValue(t *testing.T) {
+	assert.Equal(t, "5432", ExampleFieldValue(schema.CredentialField{Name: "Port", Type: schema.FieldTypePort, Default: "5432"}))
+	assert.Equal(t, "live", ExampleFieldValue(schema.CredentialField{Name: "Mode", Enum: []string{"live", "test"}}))
+	assert.Equal(t, "https://example.okta.com", ExampleFieldValue(schema.CredentialField{Name: "Org URL", Type: schema.FieldTypeURL, Placeholder: "https://example.okta.com"}))
+	assert.Equal(t, "example.com", ExampleFieldValue(schema.CredentialField{Name: "Host", Type: schema.FieldTypeHostname}))
+	assert.Equal(t, "", ExampleFieldValue(schema.CredentialField{Name: "Profile"}))
+}
@@ -0,0 +1,167 @@
+package plugintest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const stsNamespace = "https://sts.amazonaws.com/doc/2011-06-15/"
+
+// FakeSTS is a local stand-in for the AWS Security Token Service, which can be used to test provisioners that
+// exchange long-lived access keys for temporary credentials. It supports the GetSessionToken and AssumeRole
+// actions and validates the MFA code against ValidTOTPCode.
+type FakeSTS struct {
+	*httptest.Server
+
+	// ValidTOTPCode is the only MFA code that gets accepted. Defaults to "123456".
+	ValidTOTPCode string
+
+	// AccessKeyID, SecretAccessKey and SessionToken are returned as the temporary credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+// NewFakeSTS starts a fake STS endpoint that gets shut down when the test finishes. Point the STS client
+// to its URL to use it.
+func NewFakeSTS(t *testing.T) *FakeSTS {
+	t.Helper()
+
+	fake := &FakeSTS{
+		ValidTOTPCode:   "123456",
+		AccessKeyID:     "ASIAFAKESTS000EXAMPLE",
+		SecretAccessKey: "fakeStsSecretAccessKey0000000000EXAMPLE",
+		SessionToken:    "FakeSTSSessionTokenEXAMPLE",
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+// Requests returns the form values of all requests the fake STS endpoint received, in order.
+func (f *FakeSTS) Requests() []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requests := make([]url.Values, len(f.requests))
+	copy(requests, f.requests)
+	return requests
+}
+
+func (f *FakeSTS) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		f.writeError(w, http.StatusBadRequest, "InvalidParameterValue", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r.PostForm)
+	f.mu.Unlock()
+
+	action := r.PostForm.Get("Action")
+	if action != "GetSessionToken" && action != "AssumeRole" {
+		f.writeError(w, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("Could not find operation %s", action))
+		return
+	}
+
+	if serial := r.PostForm.Get("SerialNumber"); serial != "" && r.PostForm.Get("TokenCode") != f.ValidTOTPCode {
+		f.writeError(w, http.StatusForbidden, "AccessDenied", "MultiFactorAuthentication failed with invalid MFA one time pass code.")
+		return
+	}
+
+	duration := 3600
+	if value := r.PostForm.Get("DurationSeconds"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 900 {
+			f.writeError(w, http.StatusBadRequest, "ValidationError", "DurationSeconds must be at least 900")
+			return
+		}
+		duration = parsed
+	}
+
+	credentials := fakeSTSCredentials{
+		AccessKeyID:     f.AccessKeyID,
+		SecretAccessKey: f.SecretAccessKey,
+		SessionToken:    f.SessionToken,
+		Expiration:      time.Now().Add(time.Duration(duration) * time.Second).UTC().Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	if action == "GetSessionToken" {
+		_ = xml.NewEncoder(w).Encode(fakeSTSGetSessionTokenResponse{
+			Namespace:   stsNamespace,
+			Credentials: credentials,
+		})
+		return
+	}
+
+	_ = xml.NewEncoder(w).Encode(fakeSTSAssumeRoleResponse{
+		Namespace:   stsNamespace,
+		Credentials: credentials,
+		AssumedRoleUser: fakeSTSAssumedRoleUser{
+			Arn:           r.PostForm.Get("RoleArn") + "/" + r.PostForm.Get("RoleSessionName"),
+			AssumedRoleID: "AROAFAKESTS000EXAMPLE:" + r.PostForm.Get("RoleSessionName"),
+		},
+	})
+}
+
+func (f *FakeSTS) writeError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(fakeSTSErrorResponse{
+		Namespace: stsNamespace,
+		Error: fakeSTSError{
+			Type:    "Sender",
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+type fakeSTSCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+type fakeSTSGetSessionTokenResponse struct {
+	XMLName     xml.Name           `xml:"GetSessionTokenResponse"`
+	Namespace   string             `xml:"xmlns,attr"`
+	Credentials fakeSTSCredentials `xml:"GetSessionTokenResult>Credentials"`
+}
+
+type fakeSTSAssumedRoleUser struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleID string `xml:"AssumedRoleId"`
+}
+
+type fakeSTSAssumeRoleResponse struct {
+	XMLName         xml.Name               `xml:"AssumeRoleResponse"`
+	Namespace       string                 `xml:"xmlns,attr"`
+	Credentials     fakeSTSCredentials     `xml:"AssumeRoleResult>Credentials"`
+	AssumedRoleUser fakeSTSAssumedRoleUser `xml:"AssumeRoleResult>AssumedRoleUser"`
+}
+
+type fakeSTSError struct {
+	Type    string `xml:"Type"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type fakeSTSErrorResponse struct {
+	XMLName   xml.Name     `xml:"ErrorResponse"`
+	Namespace string       `xml:"xmlns,attr"`
+	Error     fakeSTSError `xml:"Error"`
+}
@@ -56,6 +56,10 @@ func TestImporter(t *testing.T, importer sdk.Importer, cases map[string]ImportCa
 				assert.ElementsMatch(t, c.ExpectedCandidates, out.AllCandidates(), description)
 			}
 
+			if c.ExpectedWarnings != nil {
+				assert.ElementsMatch(t, c.ExpectedWarnings, out.Warnings(), description)
+			}
+
 			for envVarName := range c.Environment {
 				t.Setenv(envVarName, "")
 			}
@@ -77,4 +81,8 @@ type ImportCase struct {
 
 	// ExpectedOutput can be used to set the exact expected import output. Mutually exclusive with ExpectedCandidates.
 	ExpectedOutput *sdk.ImportOutput
+
+	// (Optional) ExpectedWarnings can be used to check the warnings reported by the importer, in addition to the
+	// expected import candidates.
+	ExpectedWarnings []sdk.Warning
 }
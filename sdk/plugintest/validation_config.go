@@ -0,0 +1,136 @@
+package plugintest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// ValidationConfig configures how the validation checks of a project are reported: which failed checks are waived,
+// which severity checks have and whether warnings should fail the validation.
+type ValidationConfig struct {
+	// (Optional) Failed checks that are known and accepted, for example because the platform has no page to manage
+	// the credential.
+	Suppressions []ValidationSuppression `yaml:"suppressions"`
+
+	// (Optional) The severity of checks by check ID, overriding their default severity.
+	Severities map[string]schema.ValidationSeverity `yaml:"severities"`
+
+	// (Optional) Whether to promote all warnings to errors.
+	Strict bool `yaml:"strict"`
+}
+
+// ValidationSuppression waives the failed checks with the specified ID, optionally only for a single subject.
+type ValidationSuppression struct {
+	// The ID of the check to suppress, e.g. "credential-management-url".
+	Check string `yaml:"check"`
+
+	// (Optional) The plugin to suppress the check for. Suppresses the check for all plugins if empty.
+	Plugin string `yaml:"plugin"`
+
+	// (Optional) The heading of the report to suppress the check in, e.g. "Credential: Auth Token". Suppresses the
+	// check in all reports of the plugin if empty.
+	Heading string `yaml:"heading"`
+
+	// (Optional) The subject of the check to suppress, for checks that are run for each field, executable, etc., e.g.
+	// "Token" to suppress the check for the Token field only. Suppresses the check for all subjects if empty.
+	Subject string `yaml:"subject"`
+
+	// Why the check is suppressed.
+	Reason string `yaml:"reason"`
+}
+
+// LoadValidationConfig reads the validation config from the YAML file at the specified path.
+func LoadValidationConfig(path string) (ValidationConfig, error) {
+	var config ValidationConfig
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	err = yaml.UnmarshalStrict(contents, &config)
+	if err != nil {
+		return config, fmt.Errorf("parsing validation config %s: %w", path, err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return config, fmt.Errorf("invalid validation config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks whether all suppressions have a check ID and a reason, and whether the severities are known.
+func (c ValidationConfig) Validate() error {
+	for i, suppression := range c.Suppressions {
+		if suppression.Check == "" {
+			return fmt.Errorf("suppression %d has no check ID set", i+1)
+		}
+		if suppression.Reason == "" {
+			return fmt.Errorf("suppression of '%s' has no reason set", suppression.Check)
+		}
+	}
+
+	for id, severity := range c.Severities {
+		if severity != schema.ValidationSeverityWarning && severity != schema.ValidationSeverityError {
+			return fmt.Errorf("severity of '%s' should be '%s' or '%s', got '%s'", id, schema.ValidationSeverityWarning, schema.ValidationSeverityError, severity)
+		}
+	}
+
+	return nil
+}
+
+// Apply returns the validation results with the configured severities, strict mode and suppressions applied, in
+// that order. The results that are passed in are not modified.
+func (c ValidationConfig) Apply(results []PluginValidationReports) []PluginValidationReports {
+	var applied []PluginValidationReports
+	for _, result := range results {
+		var reports []schema.ValidationReport
+		for _, report := range result.Reports {
+			checks := []schema.ValidationCheck{}
+			for _, check := range report.Checks {
+				if severity, ok := c.Severities[check.ID]; ok {
+					check.Severity = severity
+				}
+
+				if c.Strict && check.Severity == schema.ValidationSeverityWarning {
+					check.Severity = schema.ValidationSeverityError
+				}
+
+				if !check.Assertion {
+					if suppression, ok := c.suppression(result.Plugin, report.Heading, check); ok {
+						check.Suppression = suppression.Reason
+					}
+				}
+
+				checks = append(checks, check)
+			}
+			reports = append(reports, schema.ValidationReport{Heading: report.Heading, Checks: checks})
+		}
+		applied = append(applied, PluginValidationReports{Plugin: result.Plugin, Reports: reports})
+	}
+	return applied
+}
+
+func (c ValidationConfig) suppression(plugin string, heading string, check schema.ValidationCheck) (ValidationSuppression, bool) {
+	for _, suppression := range c.Suppressions {
+		if suppression.Check != check.ID {
+			continue
+		}
+		if suppression.Subject != "" && suppression.Subject != check.Subject {
+			continue
+		}
+		if suppression.Plugin != "" && suppression.Plugin != plugin {
+			continue
+		}
+		if suppression.Heading != "" && suppression.Heading != heading {
+			continue
+		}
+		return suppression, true
+	}
+	return ValidationSuppression{}, false
+}
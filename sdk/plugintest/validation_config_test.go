@@ -0,0 +1,181 @@
+package plugintest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationConfigApply(t *testing.T) {
+	for description, scenario := range map[string]struct {
+		config     ValidationConfig
+		severities []schema.ValidationSeverity
+		suppressed []bool
+	}{
+		"no config": {
+			config:     ValidationConfig{},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityWarning, schema.ValidationSeverityWarning, schema.ValidationSeverityError},
+			suppressed: []bool{false, false, false},
+		},
+		"strict": {
+			config:     ValidationConfig{Strict: true},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityError, schema.ValidationSeverityError, schema.ValidationSeverityError},
+			suppressed: []bool{false, false, false},
+		},
+		"severity override": {
+			config: ValidationConfig{
+				Severities: map[string]schema.ValidationSeverity{"credential-secret-field": schema.ValidationSeverityWarning},
+			},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityWarning, schema.ValidationSeverityWarning, schema.ValidationSeverityWarning},
+			suppressed: []bool{false, false, false},
+		},
+		"suppression": {
+			config: ValidationConfig{
+				Suppressions: []ValidationSuppression{
+					{Check: "credential-management-url", Plugin: "example", Reason: "no management page"},
+					// Passed checks are never marked as suppressed
+					{Check: "credential-docs-url", Reason: "no docs"},
+				},
+			},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityWarning, schema.ValidationSeverityWarning, schema.ValidationSeverityError},
+			suppressed: []bool{false, true, false},
+		},
+		"suppression for other plugin": {
+			config: ValidationConfig{
+				Suppressions: []ValidationSuppression{
+					{Check: "credential-management-url", Plugin: "other", Reason: "no management page"},
+				},
+			},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityWarning, schema.ValidationSeverityWarning, schema.ValidationSeverityError},
+			suppressed: []bool{false, false, false},
+		},
+		"suppression for other heading": {
+			config: ValidationConfig{
+				Suppressions: []ValidationSuppression{
+					{Check: "credential-management-url", Heading: "Credential: Auth Token", Reason: "no management page"},
+				},
+			},
+			severities: []schema.ValidationSeverity{schema.ValidationSeverityWarning, schema.ValidationSeverityWarning, schema.ValidationSeverityError},
+			suppressed: []bool{false, false, false},
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			original := exampleValidationResults()
+			results := scenario.config.Apply(original)
+			assert.Equal(t, exampleValidationResults(), original, "results that are passed in should not be modified")
+
+			checks := results[0].Reports[0].Checks
+			require.Len(t, checks, len(scenario.severities))
+			for i, check := range checks {
+				assert.Equal(t, scenario.severities[i], check.Severity, check.ID)
+				assert.Equal(t, scenario.suppressed[i], check.IsSuppressed(), check.ID)
+			}
+		})
+	}
+}
+
+func TestValidationConfigApplySubject(t *testing.T) {
+	results := []PluginValidationReports{
+		{
+			Plugin: "example",
+			Reports: []schema.ValidationReport{
+				{
+					Heading: "Registry: example",
+					Checks: []schema.ValidationCheck{
+						{ID: "registry-field-name", Subject: "Token", Assertion: false, Severity: schema.ValidationSeverityError},
+						{ID: "registry-field-name", Subject: "Magic Number", Assertion: false, Severity: schema.ValidationSeverityError},
+					},
+				},
+			},
+		},
+	}
+	config := ValidationConfig{
+		Suppressions: []ValidationSuppression{
+			{Check: "registry-field-name", Subject: "Magic Number", Reason: "only used by this plugin"},
+		},
+	}
+
+	checks := config.Apply(results)[0].Reports[0].Checks
+	assert.False(t, checks[0].IsSuppressed(), "checks for other subjects should not be suppressed")
+	assert.True(t, checks[1].IsSuppressed())
+}
+
+func TestValidationConfigApplyHasErrors(t *testing.T) {
+	config := ValidationConfig{
+		Suppressions: []ValidationSuppression{
+			{Check: "credential-secret-field", Reason: "no secrets"},
+		},
+	}
+	report := config.Apply(exampleValidationResults())[0].Reports[0]
+	assert.False(t, report.HasErrors())
+	assert.False(t, report.IsValid())
+
+	config.Strict = true
+	report = config.Apply(exampleValidationResults())[0].Reports[0]
+	assert.True(t, report.HasErrors())
+}
+
+func TestLoadValidationConfig(t *testing.T) {
+	for description, scenario := range map[string]struct {
+		contents string
+		expected ValidationConfig
+		err      string
+	}{
+		"valid": {
+			contents: `
+suppressions:
+  - check: credential-management-url
+    plugin: vault
+    reason: self-hosted
+severities:
+  credential-docs-url: error
+strict: true
+`,
+			expected: ValidationConfig{
+				Suppressions: []ValidationSuppression{
+					{Check: "credential-management-url", Plugin: "vault", Reason: "self-hosted"},
+				},
+				Severities: map[string]schema.ValidationSeverity{"credential-docs-url": schema.ValidationSeverityError},
+				Strict:     true,
+			},
+		},
+		"suppression without reason": {
+			contents: `
+suppressions:
+  - check: credential-management-url
+`,
+			err: "suppression of 'credential-management-url' has no reason set",
+		},
+		"unknown severity": {
+			contents: `
+severities:
+  credential-docs-url: fatal
+`,
+			err: "severity of 'credential-docs-url' should be 'warning' or 'error', got 'fatal'",
+		},
+		"unknown key": {
+			contents: `
+stict: true
+`,
+			err: "field stict not found",
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "validation.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(scenario.contents), 0600))
+
+			config, err := LoadValidationConfig(path)
+			if scenario.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, scenario.expected, config)
+		})
+	}
+}
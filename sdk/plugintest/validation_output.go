@@ -0,0 +1,337 @@
+package plugintest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/1Password/shell-plugins/sdk/schema"
+)
+
+// ValidationOutputFormat is a format the validation reports can be written in.
+type ValidationOutputFormat string
+
+const (
+	// ValidationOutputText is the colored, human-readable format of ValidationReportPrinter.
+	ValidationOutputText ValidationOutputFormat = "text"
+
+	// ValidationOutputJSON lists all checks and a summary with the number of errors and warnings.
+	ValidationOutputJSON ValidationOutputFormat = "json"
+
+	// ValidationOutputJUnit is the JUnit XML format, with a test suite per report and a test case per check.
+	ValidationOutputJUnit ValidationOutputFormat = "junit"
+
+	// ValidationOutputSARIF is the SARIF 2.1.0 format, which can be used to annotate pull requests.
+	ValidationOutputSARIF ValidationOutputFormat = "sarif"
+)
+
+// PluginValidationReports holds the validation reports of a single plugin.
+type PluginValidationReports struct {
+	Plugin  string
+	Reports []schema.ValidationReport
+}
+
+// WriteValidationReports writes the validation reports of the plugins in the specified format.
+func WriteValidationReports(w io.Writer, format ValidationOutputFormat, results []PluginValidationReports) error {
+	switch format {
+	case ValidationOutputText, "":
+		for _, result := range results {
+			printer := &ValidationReportPrinter{
+				Reports: result.Reports,
+				Format:  PrintFormat{}.ValidationReportFormat(),
+				Output:  w,
+			}
+			printer.Print()
+		}
+		return nil
+	case ValidationOutputJSON:
+		return writeJSON(w, results)
+	case ValidationOutputJUnit:
+		return writeJUnit(w, results)
+	case ValidationOutputSARIF:
+		return writeSARIF(w, results)
+	default:
+		return fmt.Errorf("unknown validation output format '%s', expected one of: text, json, junit, sarif", format)
+	}
+}
+
+// ValidationSummary counts the checks by outcome.
+type ValidationSummary struct {
+	Passed     int `json:"passed"`
+	Warnings   int `json:"warnings"`
+	Errors     int `json:"errors"`
+	Suppressed int `json:"suppressed"`
+}
+
+// SummarizeValidationReports counts the passed checks, failed warnings, failed errors and suppressed checks in the
+// reports.
+func SummarizeValidationReports(results []PluginValidationReports) ValidationSummary {
+	var summary ValidationSummary
+	for _, result := range results {
+		for _, report := range result.Reports {
+			for _, check := range report.Checks {
+				switch {
+				case check.Assertion:
+					summary.Passed++
+				case check.IsSuppressed():
+					summary.Suppressed++
+				case check.Severity == schema.ValidationSeverityWarning:
+					summary.Warnings++
+				default:
+					summary.Errors++
+				}
+			}
+		}
+	}
+	return summary
+}
+
+type jsonOutput struct {
+	Summary ValidationSummary `json:"summary"`
+	Plugins []jsonPlugin      `json:"plugins"`
+}
+
+type jsonPlugin struct {
+	Name    string       `json:"name"`
+	Reports []jsonReport `json:"reports"`
+}
+
+type jsonReport struct {
+	Heading string      `json:"heading"`
+	Checks  []jsonCheck `json:"checks"`
+}
+
+type jsonCheck struct {
+	ID          string `json:"id"`
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Passed      bool   `json:"passed"`
+	Suppression string `json:"suppression,omitempty"`
+}
+
+func writeJSON(w io.Writer, results []PluginValidationReports) error {
+	output := jsonOutput{
+		Summary: SummarizeValidationReports(results),
+		Plugins: []jsonPlugin{},
+	}
+	for _, result := range results {
+		plugin := jsonPlugin{Name: result.Plugin, Reports: []jsonReport{}}
+		for _, report := range result.Reports {
+			r := jsonReport{Heading: report.Heading, Checks: []jsonCheck{}}
+			for _, check := range report.Checks {
+				r.Checks = append(r.Checks, jsonCheck{
+					ID:          check.ID,
+					Subject:     check.Subject,
+					Description: check.Description,
+					Severity:    string(check.Severity),
+					Passed:      check.Assertion,
+					Suppression: check.Suppression,
+				})
+			}
+			plugin.Reports = append(plugin.Reports, r)
+		}
+		output.Plugins = append(output.Plugins, plugin)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes a test suite per report and a test case per check. JUnit has no notion of warnings, so failed
+// warnings are reported as passed test cases with the warning as output, to not fail the build. Suppressed checks are
+// reported as skipped.
+func writeJUnit(w io.Writer, results []PluginValidationReports) error {
+	suites := junitTestSuites{Name: "shell-plugins validation"}
+	for _, result := range results {
+		for _, report := range result.Reports {
+			suite := junitTestSuite{Name: fmt.Sprintf("%s: %s", result.Plugin, report.Heading)}
+			for _, check := range report.Checks {
+				testCase := junitTestCase{
+					Name:      fmt.Sprintf("%s: %s", check.ID, check.Description),
+					ClassName: fmt.Sprintf("%s.%s", result.Plugin, check.ID),
+				}
+				if !check.Assertion {
+					if check.Severity == schema.ValidationSeverityWarning {
+						testCase.SystemOut = "warning: " + check.Description
+					} else {
+						testCase.Failure = &junitFailure{Message: check.Description, Type: string(check.Severity)}
+						suite.Failures++
+					}
+				}
+				suite.Cases = append(suite.Cases, testCase)
+				suite.Tests++
+			}
+			suites.Tests += suite.Tests
+			suites.Failures += suite.Failures
+			suites.Skipped += suite.Skipped
+			suites.Suites = append(suites.Suites, suite)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// writeSARIF writes a result for each failed check, located at the plugin.go file of the plugin. Every check ID
+// becomes a rule, described by the first check with that ID. Suppressed checks are marked as suppressed externally,
+// since the suppression lives in the validation config rather than next to the code.
+func writeSARIF(w io.Writer, results []PluginValidationReports) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "shell-plugins-validate",
+				InformationURI: "https://github.com/1Password/shell-plugins",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+	for _, result := range results {
+		for _, report := range result.Reports {
+			for _, check := range report.Checks {
+				if !rules[check.ID] {
+					rules[check.ID] = true
+					run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+						ID:               check.ID,
+						ShortDescription: sarifMessage{Text: check.Description},
+					})
+				}
+
+				if check.Assertion {
+					continue
+				}
+
+				level := "error"
+				if check.Severity == schema.ValidationSeverityWarning {
+					level = "warning"
+				}
+				failure := sarifResult{
+					RuleID:  check.ID,
+					Level:   level,
+					Message: sarifMessage{Text: fmt.Sprintf("%s: %s", report.Heading, check.Description)},
+					Locations: []sarifLocation{
+						{
+							PhysicalLocation: sarifPhysicalLocation{
+								ArtifactLocation: sarifArtifactLocation{URI: fmt.Sprintf("plugins/%s/plugin.go", result.Plugin)},
+							},
+						},
+					},
+				}
+				if check.IsSuppressed() {
+					failure.Suppressions = []sarifSuppression{{Kind: "external", Justification: check.Suppression}}
+				}
+				run.Results = append(run.Results, failure)
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,100 @@
+package plugintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exampleValidationResults() []PluginValidationReports {
+	return []PluginValidationReports{
+		{
+			Plugin: "example",
+			Reports: []schema.ValidationReport{
+				{
+					Heading: "Credential: API Key",
+					Checks: []schema.ValidationCheck{
+						{ID: "credential-docs-url", Description: "Has a documentation URL set", Assertion: true, Severity: schema.ValidationSeverityWarning},
+						{ID: "credential-management-url", Description: "Has a management URL set", Assertion: false, Severity: schema.ValidationSeverityWarning},
+						{ID: "credential-secret-field", Description: "Has at least 1 field that is secret", Assertion: false, Severity: schema.ValidationSeverityError},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSummarizeValidationReports(t *testing.T) {
+	summary := SummarizeValidationReports(exampleValidationResults())
+	assert.Equal(t, ValidationSummary{Passed: 1, Warnings: 1, Errors: 1}, summary)
+}
+
+func TestWriteValidationReportsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteValidationReports(&buf, ValidationOutputJSON, exampleValidationResults())
+	require.NoError(t, err)
+
+	var output jsonOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	assert.Equal(t, ValidationSummary{Passed: 1, Warnings: 1, Errors: 1}, output.Summary)
+	require.Len(t, output.Plugins, 1)
+	assert.Equal(t, "example", output.Plugins[0].Name)
+	require.Len(t, output.Plugins[0].Reports[0].Checks, 3)
+	assert.Equal(t, jsonCheck{
+		ID:          "credential-secret-field",
+		Description: "Has at least 1 field that is secret",
+		Severity:    "error",
+		Passed:      false,
+	}, output.Plugins[0].Reports[0].Checks[2])
+}
+
+func TestWriteValidationReportsJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteValidationReports(&buf, ValidationOutputJUnit, exampleValidationResults())
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+
+	suite := suites.Suites[0]
+	assert.Equal(t, "example: Credential: API Key", suite.Name)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Nil(t, suite.Cases[1].Failure)
+	assert.Equal(t, "warning: Has a management URL set", suite.Cases[1].SystemOut)
+	require.NotNil(t, suite.Cases[2].Failure)
+	assert.Equal(t, "example.credential-secret-field", suite.Cases[2].ClassName)
+}
+
+func TestWriteValidationReportsSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteValidationReports(&buf, ValidationOutputSARIF, exampleValidationResults())
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 3)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "credential-management-url", run.Results[0].RuleID)
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, "credential-secret-field", run.Results[1].RuleID)
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, "Credential: API Key: Has at least 1 field that is secret", run.Results[1].Message.Text)
+	assert.Equal(t, "plugins/example/plugin.go", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestWriteValidationReportsUnknownFormat(t *testing.T) {
+	err := WriteValidationReports(&bytes.Buffer{}, "yaml", exampleValidationResults())
+	assert.EqualError(t, err, "unknown validation output format 'yaml', expected one of: text, json, junit, sarif")
+}
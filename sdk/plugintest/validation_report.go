@@ -2,6 +2,7 @@ package plugintest
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/1Password/shell-plugins/sdk/schema"
 	"github.com/fatih/color"
@@ -56,11 +57,21 @@ func (pf PrintFormat) ValidationReportFormat() PrintFormat {
 type ValidationReportPrinter struct {
 	Reports []schema.ValidationReport
 	Format  PrintFormat
+
+	// (Optional) Where to print the reports to. Defaults to standard output.
+	Output io.Writer
+}
+
+func (p *ValidationReportPrinter) output() io.Writer {
+	if p.Output == nil {
+		return color.Output
+	}
+	return p.Output
 }
 
 func (p *ValidationReportPrinter) Print() {
 	if p.Reports == nil || len(p.Reports) == 0 {
-		color.Cyan("No reports to print")
+		color.New(color.FgCyan).Fprintln(p.output(), "No reports to print")
 		return
 	}
 
@@ -74,14 +85,14 @@ func (p *ValidationReportPrinter) PrintReport(report schema.ValidationReport) {
 	p.printChecks(report.Checks)
 }
 
-// sortChecks in the order ["success", "warning", "error"]
+// sortChecks in the order ["success", "warning", "error"], with suppressed checks counting as success
 func (p *ValidationReportPrinter) sortChecks(checks []schema.ValidationCheck) []schema.ValidationCheck {
 	var successChecks []schema.ValidationCheck
 	var warningChecks []schema.ValidationCheck
 	var errorChecks []schema.ValidationCheck
 
 	for _, c := range checks {
-		if c.Assertion {
+		if c.Assertion || c.IsSuppressed() {
 			successChecks = append(successChecks, c)
 			continue
 		}
@@ -104,23 +115,28 @@ func (p *ValidationReportPrinter) printChecks(checks []schema.ValidationCheck) {
 	for _, c := range p.sortChecks(checks) {
 		p.printCheck(c)
 	}
-	fmt.Println()
+	fmt.Fprintln(p.output())
 }
 
 func (p *ValidationReportPrinter) printHeading(heading string) {
-	p.Format.Heading.Printf("# %s\n\n", heading)
+	p.Format.Heading.Fprintf(p.output(), "# %s\n\n", heading)
 }
 
 func (p *ValidationReportPrinter) printCheck(check schema.ValidationCheck) {
 	if check.Assertion {
-		p.Format.Success.Printf("✔ %s\n", check.Description)
+		p.Format.Success.Fprintf(p.output(), "✔ %s\n", check.Description)
+		return
+	}
+
+	if check.IsSuppressed() {
+		p.Format.Success.Fprintf(p.output(), "○ %s (suppressed: %s)\n", check.Description, check.Suppression)
 		return
 	}
 
 	if check.Severity == schema.ValidationSeverityWarning {
-		p.Format.Warning.Printf("⚠ %s\n", check.Description)
+		p.Format.Warning.Fprintf(p.output(), "⚠ %s\n", check.Description)
 		return
 	}
 
-	p.Format.Error.Printf("✘ %s\n", check.Description)
+	p.Format.Error.Fprintf(p.output(), "✘ %s\n", check.Description)
 }
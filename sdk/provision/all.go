@@ -0,0 +1,54 @@
+package provision
+
+import (
+	"context"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+)
+
+// ChainedProvisioner runs multiple provisioners for the same credential.
+type ChainedProvisioner struct {
+	sdk.Provisioner
+
+	Provisioners []sdk.Provisioner
+}
+
+// All creates a ChainedProvisioner that runs the specified provisioners in order, combining their output
+// into a single provision output. This is useful for credentials that need to be provisioned in more than
+// one way, for example as both an environment variable and a config file.
+func All(provisioners ...sdk.Provisioner) sdk.Provisioner {
+	return ChainedProvisioner{
+		Provisioners: provisioners,
+	}
+}
+
+func (p ChainedProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	for _, provisioner := range p.Provisioners {
+		errCount := len(out.Diagnostics.Errors)
+		provisioner.Provision(ctx, in, out)
+
+		// Provisioning is considered failed as soon as one of the provisioners reports an error,
+		// so there's no use in running the remaining ones.
+		if len(out.Diagnostics.Errors) > errCount {
+			return
+		}
+	}
+}
+
+func (p ChainedProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	// Deprovision in reverse order, so that provisioners that build on the output of earlier
+	// ones get cleaned up first.
+	for i := len(p.Provisioners) - 1; i >= 0; i-- {
+		p.Provisioners[i].Deprovision(ctx, in, out)
+	}
+}
+
+func (p ChainedProvisioner) Description() string {
+	var descriptions []string
+	for _, provisioner := range p.Provisioners {
+		descriptions = append(descriptions, provisioner.Description())
+	}
+
+	return strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,77 @@
+package provision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllProvisioner(t *testing.T) {
+	plugintest.TestProvisioner(t, All(
+		EnvVars(map[string]sdk.FieldName{
+			"VAULT_ADDR": fieldname.Address,
+		}),
+		TempFile(FieldAsFile(fieldname.Token), AtFixedPath("~/.vault-token")),
+	), map[string]plugintest.ProvisionCase{
+		"env var and file": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.Address: "https://vault.acme.com",
+				fieldname.Token:   "hvs.ExampleToken",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"VAULT_ADDR": "https://vault.acme.com",
+				},
+				Files: map[string]sdk.OutputFile{
+					"~/.vault-token": {Contents: []byte("hvs.ExampleToken")},
+				},
+			},
+		},
+		"stops after first error": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.Address: "https://vault.acme.com",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"VAULT_ADDR": "https://vault.acme.com",
+				},
+				Diagnostics: sdk.Diagnostics{
+					Errors: []sdk.Error{{Message: "no value present in the item for field 'Token'"}},
+				},
+			},
+		},
+	})
+}
+
+type recordingProvisioner struct {
+	sdk.Provisioner
+
+	name string
+	log  *[]string
+}
+
+func (p recordingProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+	*p.log = append(*p.log, p.name)
+}
+
+func (p recordingProvisioner) Description() string {
+	return p.name
+}
+
+func TestAllDeprovisionReverseOrder(t *testing.T) {
+	var log []string
+	p := All(
+		recordingProvisioner{name: "first", log: &log},
+		recordingProvisioner{name: "second", log: &log},
+		recordingProvisioner{name: "third", log: &log},
+	)
+
+	p.Deprovision(context.Background(), sdk.DeprovisionInput{}, &sdk.DeprovisionOutput{})
+
+	assert.Equal(t, []string{"third", "second", "first"}, log)
+	assert.Equal(t, "first; second; third", p.Description())
+}
@@ -1,9 +1,14 @@
 package provision
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/1Password/shell-plugins/sdk"
 )
@@ -13,33 +18,216 @@ type EnvVarProvisioner struct {
 	sdk.Provisioner
 
 	Schema map[string]sdk.FieldName
+
+	derived    map[string]ItemToValue
+	transforms map[string]ValueTransform
+	defaults   map[string]string
 }
 
+// ItemToValue derives the value of an environment variable from the 1Password item. An empty value
+// means that the environment variable should not be set. The context is the one of the provision step.
+type ItemToValue func(ctx context.Context, in sdk.ProvisionInput) (string, error)
+
+// ValueTransform transforms a field value before it gets provisioned as an environment variable.
+type ValueTransform func(value string) (string, error)
+
 // EnvVars creates an EnvVarProvisioner that provisions secrets as environment variables, based
 // on the specified schema of field name and environment variable name.
-func EnvVars(schema map[string]sdk.FieldName) sdk.Provisioner {
-	return EnvVarProvisioner{
+func EnvVars(schema map[string]sdk.FieldName, opts ...EnvVarOption) sdk.Provisioner {
+	p := EnvVarProvisioner{
 		Schema: schema,
 	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// EnvVarOption can be used to influence the behavior of the environment variable provisioner.
+type EnvVarOption func(*EnvVarProvisioner)
+
+// DerivedEnvVar can be used to provision an environment variable whose value is derived from one or more
+// fields, instead of mapping a single field one-to-one.
+func DerivedEnvVar(envVarName string, value ItemToValue) EnvVarOption {
+	return func(p *EnvVarProvisioner) {
+		if p.derived == nil {
+			p.derived = make(map[string]ItemToValue)
+		}
+		p.derived[envVarName] = value
+	}
+}
+
+// Transform can be used to transform the value of an environment variable before it gets provisioned,
+// for example to lowercase a region. Applies to both schema-mapped and derived environment variables.
+func Transform(envVarName string, transform ValueTransform) EnvVarOption {
+	return func(p *EnvVarProvisioner) {
+		if p.transforms == nil {
+			p.transforms = make(map[string]ValueTransform)
+		}
+		p.transforms[envVarName] = transform
+	}
+}
+
+// Default can be used to provision a default value for an environment variable if the
+// item does not have a value for the corresponding field.
+func Default(envVarName string, value string) EnvVarOption {
+	return func(p *EnvVarProvisioner) {
+		if p.defaults == nil {
+			p.defaults = make(map[string]string)
+		}
+		p.defaults[envVarName] = value
+	}
 }
 
 func (p EnvVarProvisioner) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
 	for envVarName, fieldName := range p.Schema {
-		if value, ok := in.ItemFields[fieldName]; ok {
+		value, ok := in.ItemFields[fieldName]
+		if err := p.provisionValue(envVarName, value, ok, out); err != nil {
+			out.AddError(err)
+			return
+		}
+	}
+
+	for envVarName, derive := range p.derived {
+		value, err := derive(ctx, in)
+		if err != nil {
+			out.AddError(fmt.Errorf("deriving value for %s: %w", envVarName, err))
+			return
+		}
+
+		if err := p.provisionValue(envVarName, value, value != "", out); err != nil {
+			out.AddError(err)
+			return
+		}
+	}
+
+	// Add the defaults for environment variables that are neither mapped to a field nor derived.
+	for envVarName, value := range p.defaults {
+		if _, ok := out.Environment[envVarName]; !ok {
 			out.AddEnvVar(envVarName, value)
 		}
 	}
 }
 
+func (p EnvVarProvisioner) provisionValue(envVarName string, value string, present bool, out *sdk.ProvisionOutput) error {
+	if !present {
+		if defaultValue, ok := p.defaults[envVarName]; ok {
+			out.AddEnvVar(envVarName, defaultValue)
+		}
+		return nil
+	}
+
+	if transform, ok := p.transforms[envVarName]; ok {
+		transformed, err := transform(value)
+		if err != nil {
+			return fmt.Errorf("transforming value for %s: %w", envVarName, err)
+		}
+		value = transformed
+	}
+
+	out.AddEnvVar(envVarName, value)
+	return nil
+}
+
 func (p EnvVarProvisioner) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
 	// Nothing to do here: environment variables get wiped automatically when the process exits.
 }
 
 func (p EnvVarProvisioner) Description() string {
+	return fmt.Sprintf("Provision environment variables: %s", strings.Join(p.EnvVarNames(), ", "))
+}
+
+// EnvVarNames returns the sorted names of all environment variables this provisioner can set.
+func (p EnvVarProvisioner) EnvVarNames() []string {
+	seen := make(map[string]bool)
 	var envVarNames []string
+	add := func(envVarName string) {
+		if !seen[envVarName] {
+			seen[envVarName] = true
+			envVarNames = append(envVarNames, envVarName)
+		}
+	}
+
 	for envVarName := range p.Schema {
-		envVarNames = append(envVarNames, envVarName)
+		add(envVarName)
+	}
+	for envVarName := range p.derived {
+		add(envVarName)
 	}
+	for envVarName := range p.defaults {
+		add(envVarName)
+	}
+
+	sort.Strings(envVarNames)
+	return envVarNames
+}
+
+// Lowercase transforms the value to lowercase.
+func Lowercase(value string) (string, error) {
+	return strings.ToLower(value), nil
+}
 
-	return fmt.Sprintf("Provision environment variables: %s", strings.Join(envVarNames, ", "))
+// Uppercase transforms the value to uppercase.
+func Uppercase(value string) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+// Base64 transforms the value to its standard base64 encoding.
+func Base64(value string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(value)), nil
+}
+
+// BasicAuth derives the base64 encoding of "user:password", as used in HTTP basic authentication.
+// Nothing gets provisioned if the item has no value for the user field.
+func BasicAuth(userField sdk.FieldName, passwordField sdk.FieldName) ItemToValue {
+	return func(ctx context.Context, in sdk.ProvisionInput) (string, error) {
+		user, ok := in.ItemFields[userField]
+		if !ok {
+			return "", nil
+		}
+		return base64.StdEncoding.EncodeToString([]byte(user + ":" + in.ItemFields[passwordField])), nil
+	}
+}
+
+// FromTemplate derives a value by executing the specified Go template. Field values are available
+// using the "field" function, e.g. `{{ field "Host" }}`. Referencing a field that the item has no value
+// for results in an error, so that no partial value gets provisioned.
+// Besides the builtin template functions, "userinfo" and "pathescape" can be used to escape values for
+// the user info and path of a URL. Don't use "urlquery" for these: it escapes spaces as "+", which
+// is only decoded as a space in query strings.
+// For example:
+// * `FromTemplate("https://{{ field "Host" }}/api")` will result in `https://example.com/api`.
+// * `FromTemplate("https://{{ field "User" | userinfo }}@{{ field "Host" }}")` will escape the user for use in the URL.
+func FromTemplate(tmplStr string) ItemToValue {
+	return func(ctx context.Context, in sdk.ProvisionInput) (string, error) {
+		var missingField string
+		tmpl, err := template.New("env").Funcs(template.FuncMap{
+			"field": func(name string) (string, error) {
+				value, ok := in.ItemFields[sdk.FieldName(name)]
+				if !ok {
+					missingField = name
+					return "", fmt.Errorf("item has no value for field '%s'", name)
+				}
+				return value, nil
+			},
+			"userinfo": func(value string) string {
+				return url.User(value).String()
+			},
+			"pathescape": url.PathEscape,
+		}).Parse(tmplStr)
+		if err != nil {
+			return "", err
+		}
+
+		var result bytes.Buffer
+		err = tmpl.Execute(&result, nil)
+		if missingField != "" {
+			return "", fmt.Errorf("item has no value for field '%s'", missingField)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return result.String(), nil
+	}
 }
@@ -0,0 +1,94 @@
+package provision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvVarProvisioner(t *testing.T) {
+	plugintest.TestProvisioner(t, EnvVars(
+		map[string]sdk.FieldName{
+			"EXAMPLE_USER":   fieldname.User,
+			"EXAMPLE_REGION": fieldname.Region,
+		},
+		Transform("EXAMPLE_REGION", Lowercase),
+		Default("EXAMPLE_REGION", "us-east-1"),
+		Default("EXAMPLE_OUTPUT", "json"),
+		DerivedEnvVar("EXAMPLE_AUTH", BasicAuth(fieldname.User, fieldname.Password)),
+		DerivedEnvVar("EXAMPLE_URL", FromTemplate(`https://{{ field "User" | userinfo }}@{{ field "Host" }}`)),
+	), map[string]plugintest.ProvisionCase{
+		"all fields": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.User:     "wendy appleseed",
+				fieldname.Password: "secret",
+				fieldname.Region:   "EU-CENTRAL-1",
+				fieldname.Host:     "example.com",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"EXAMPLE_USER":   "wendy appleseed",
+					"EXAMPLE_REGION": "eu-central-1",
+					"EXAMPLE_OUTPUT": "json",
+					"EXAMPLE_AUTH":   "d2VuZHkgYXBwbGVzZWVkOnNlY3JldA==",
+					"EXAMPLE_URL":    "https://wendy%20appleseed@example.com",
+				},
+			},
+		},
+		"defaults": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.User: "wendy",
+				fieldname.Host: "example.com",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"EXAMPLE_USER":   "wendy",
+					"EXAMPLE_REGION": "us-east-1",
+					"EXAMPLE_OUTPUT": "json",
+					"EXAMPLE_AUTH":   "d2VuZHk6",
+					"EXAMPLE_URL":    "https://wendy@example.com",
+				},
+			},
+		},
+		"missing template field": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.Host: "example.com",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"EXAMPLE_REGION": "us-east-1",
+				},
+				Diagnostics: sdk.Diagnostics{
+					Errors: []sdk.Error{{Message: "deriving value for EXAMPLE_URL: item has no value for field 'User'"}},
+				},
+			},
+		},
+	})
+}
+
+func TestEnvVarProvisionerDescription(t *testing.T) {
+	p := EnvVars(
+		map[string]sdk.FieldName{
+			"EXAMPLE_USER": fieldname.User,
+		},
+		Default("EXAMPLE_OUTPUT", "json"),
+		DerivedEnvVar("EXAMPLE_AUTH", BasicAuth(fieldname.User, fieldname.Password)),
+	)
+
+	assert.Equal(t, "Provision environment variables: EXAMPLE_AUTH, EXAMPLE_OUTPUT, EXAMPLE_USER", p.Description())
+}
+
+func TestFromTemplateEscaping(t *testing.T) {
+	in := sdk.ProvisionInput{ItemFields: map[sdk.FieldName]string{
+		fieldname.User:     "wendy appleseed+work",
+		fieldname.Database: "my db/prod",
+	}}
+
+	value, err := FromTemplate(`postgres://{{ field "User" | userinfo }}@localhost/{{ field "Database" | pathescape }}`)(context.Background(), in)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://wendy%20appleseed+work@localhost/my%20db%2Fprod", value)
+}
@@ -0,0 +1,96 @@
+package provision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+)
+
+// NetrcFile provisions a temporary netrc file that only contains an entry for the specified machine, with the
+// tokens (e.g. importer.NetrcLogin and importer.NetrcPassword) populated from the fields in the mapping. Entries for
+// other machines in the user's own netrc file are not carried over, so the executable can't access those.
+//
+// By default, the path of the temporary file is set as the NETRC environment variable. Not every netrc consumer reads
+// that variable: curl and git, for example, only read ~/.netrc. Pass file options that match the executable instead,
+// such as AddArgs("--netrc-file", "{{ .Path }}") for curl.
+func NetrcFile(machine string, mapping map[string]sdk.FieldName, opts ...FileOption) sdk.Provisioner {
+	if len(opts) == 0 {
+		opts = []FileOption{SetPathAsEnvVar("NETRC")}
+	}
+	opts = append([]FileOption{Filename("netrc")}, opts...)
+
+	return TempFile(netrcContents(machine, mapping), opts...)
+}
+
+func netrcContents(machine string, mapping map[string]sdk.FieldName) ItemToFileContents {
+	return func(in sdk.ProvisionInput) ([]byte, error) {
+		entry := importer.NetrcEntry{Machine: machine}
+		for token, fieldName := range mapping {
+			value, ok := in.ItemFields[fieldName]
+			if !ok {
+				return nil, fmt.Errorf("no value present in the item for field '%s'", fieldName)
+			}
+
+			switch token {
+			case importer.NetrcMachine:
+				entry.Machine = value
+			case importer.NetrcLogin:
+				entry.Login = value
+			case importer.NetrcPassword:
+				entry.Password = value
+			case importer.NetrcAccount:
+				entry.Account = value
+			default:
+				return nil, fmt.Errorf("unknown netrc token '%s'", token)
+			}
+		}
+
+		return FormatNetrc([]importer.NetrcEntry{entry}), nil
+	}
+}
+
+// FormatNetrc formats the entries in the netrc format, with one entry per line. Values are quoted if needed.
+// The default entry gets moved to the end, since netrc consumers stop looking at entries after that.
+func FormatNetrc(entries []importer.NetrcEntry) []byte {
+	var sb strings.Builder
+	var defaultEntry *importer.NetrcEntry
+	for i, entry := range entries {
+		if entry.Default {
+			defaultEntry = &entries[i]
+			continue
+		}
+		sb.WriteString("machine " + quoteNetrcValue(entry.Machine))
+		writeNetrcTokens(&sb, entry)
+	}
+
+	if defaultEntry != nil {
+		sb.WriteString("default")
+		writeNetrcTokens(&sb, *defaultEntry)
+	}
+
+	return []byte(sb.String())
+}
+
+func writeNetrcTokens(sb *strings.Builder, entry importer.NetrcEntry) {
+	if entry.Login != "" {
+		sb.WriteString(" login " + quoteNetrcValue(entry.Login))
+	}
+	if entry.Password != "" {
+		sb.WriteString(" password " + quoteNetrcValue(entry.Password))
+	}
+	if entry.Account != "" {
+		sb.WriteString(" account " + quoteNetrcValue(entry.Account))
+	}
+	sb.WriteString("\n")
+}
+
+func quoteNetrcValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"\\") && !strings.HasPrefix(value, "#") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,79 @@
+package provision
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/importer"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+)
+
+func TestNetrcFileProvisioner(t *testing.T) {
+	mapping := map[string]sdk.FieldName{
+		importer.NetrcLogin:    fieldname.Username,
+		importer.NetrcPassword: fieldname.Password,
+	}
+	itemFields := map[sdk.FieldName]string{
+		fieldname.Username: "wendy",
+		fieldname.Password: "correct horse",
+	}
+
+	plugintest.TestProvisioner(t, NetrcFile("example.com", mapping), map[string]plugintest.ProvisionCase{
+		"default": {
+			ItemFields: itemFields,
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"NETRC": "/tmp/netrc",
+				},
+				Files: map[string]sdk.OutputFile{
+					"/tmp/netrc": {Contents: []byte("machine example.com login wendy password \"correct horse\"\n")},
+				},
+			},
+		},
+	})
+
+	plugintest.TestProvisioner(t, NetrcFile("example.com", mapping, AddArgs("--netrc-file", "{{ .Path }}")), map[string]plugintest.ProvisionCase{
+		"as arg": {
+			ItemFields:  itemFields,
+			CommandLine: []string{"curl", "https://example.com"},
+			ExpectedOutput: sdk.ProvisionOutput{
+				CommandLine: []string{"curl", "https://example.com", "--netrc-file", "/tmp/netrc"},
+				Files: map[string]sdk.OutputFile{
+					"/tmp/netrc": {Contents: []byte("machine example.com login wendy password \"correct horse\"\n")},
+				},
+			},
+		},
+	})
+}
+
+func TestNetrcFileProvisionerOnlyWritesMachine(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "netrc")
+	err := os.WriteFile(existing, []byte("default login anonymous password guest\nmachine example.com login old password old\nmachine github.com login octocat password ghp_token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", existing)
+
+	plugintest.TestProvisioner(t, NetrcFile("example.com", map[string]sdk.FieldName{
+		importer.NetrcLogin:    fieldname.Username,
+		importer.NetrcPassword: fieldname.Password,
+	}), map[string]plugintest.ProvisionCase{
+		"other machines are left out": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.Username: "wendy",
+				fieldname.Password: "secret",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"NETRC": "/tmp/netrc",
+				},
+				Files: map[string]sdk.OutputFile{
+					"/tmp/netrc": {Contents: []byte("machine example.com login wendy password secret\n")},
+				},
+			},
+		},
+	})
+}
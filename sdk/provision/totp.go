@@ -0,0 +1,22 @@
+package provision
+
+import (
+	"context"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/otp"
+)
+
+// TOTPCode can be used with DerivedEnvVar to provision the current one-time code for the TOTP setup in the
+// specified field, which is either an otpauth:// URI, a base32 encoded secret or a code that was already computed
+// by the host. Provisions nothing if the field is not set. Waiting for the next code, see otp.WithMinValidity, stops
+// when the context of the provision step is cancelled.
+func TOTPCode(fieldName sdk.FieldName, opts ...otp.Option) ItemToValue {
+	return func(ctx context.Context, in sdk.ProvisionInput) (string, error) {
+		value := in.ItemFields[fieldName]
+		if value == "" {
+			return "", nil
+		}
+		return otp.Generate(ctx, value, opts...)
+	}
+}
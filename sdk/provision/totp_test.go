@@ -0,0 +1,68 @@
+package provision
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/otp"
+	"github.com/1Password/shell-plugins/sdk/plugintest"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode(t *testing.T) {
+	clock := otp.WithClock(func() time.Time { return time.Unix(1700000000, 0) })
+
+	plugintest.TestProvisioner(t, EnvVars(
+		map[string]sdk.FieldName{},
+		DerivedEnvVar("EXAMPLE_MFA_CODE", TOTPCode(fieldname.OneTimePassword, clock, otp.WithMinValidity(0))),
+	), map[string]plugintest.ProvisionCase{
+		"otpauth URI": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.OneTimePassword: "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"EXAMPLE_MFA_CODE": "324550",
+				},
+			},
+		},
+		"code computed by the host": {
+			ItemFields: map[sdk.FieldName]string{
+				fieldname.OneTimePassword: "123456",
+			},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{
+					"EXAMPLE_MFA_CODE": "123456",
+				},
+			},
+		},
+		"no TOTP setup": {
+			ItemFields: map[sdk.FieldName]string{},
+			ExpectedOutput: sdk.ProvisionOutput{
+				Environment: map[string]string{},
+			},
+		},
+	})
+}
+
+func TestTOTPCodeCancelled(t *testing.T) {
+	// The code at this time is only valid for another 10 seconds, so the provisioner waits for the next one
+	clock := otp.WithClock(func() time.Time { return time.Unix(1700000000, 0) })
+	p := EnvVars(
+		map[string]sdk.FieldName{},
+		DerivedEnvVar("EXAMPLE_MFA_CODE", TOTPCode(fieldname.OneTimePassword, clock, otp.WithMinValidity(15*time.Second))),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := sdk.ProvisionOutput{Environment: map[string]string{}, Files: map[string]sdk.OutputFile{}}
+	p.Provision(ctx, sdk.ProvisionInput{ItemFields: map[sdk.FieldName]string{
+		fieldname.OneTimePassword: "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+	}}, &out)
+
+	assert.Equal(t, []sdk.Error{{Message: "deriving value for EXAMPLE_MFA_CODE: context canceled"}}, out.Diagnostics.Errors)
+}
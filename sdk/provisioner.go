@@ -3,7 +3,9 @@ package sdk
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -39,6 +41,11 @@ type ProvisionInput struct {
 
 	// ItemFields contains the field names and their corresponding (sensitive) values.
 	ItemFields map[FieldName]string
+
+	// Environment contains the environment variables the executable gets run with. If it's nil, the environment of
+	// the current process is used. Provisioners should read environment variables through Getenv, so they can be run
+	// against an explicit environment, e.g. an empty one.
+	Environment map[string]string
 }
 
 // DeprovisionInput contains info that provisioners can use to deprovision credentials.
@@ -132,16 +139,50 @@ func (out *ProvisionOutput) AddError(err error) {
 	out.Diagnostics.Errors = append(out.Diagnostics.Errors, Error{err.Error()})
 }
 
+// AddWarning can be used to report a warning to the provision output, which gets shown to the user without causing
+// provisioning to fail.
+func (out *ProvisionOutput) AddWarning(message string) {
+	out.Diagnostics.Warnings = append(out.Diagnostics.Warnings, Warning{message})
+}
+
+// AddError can be used to report an error to the deprovision output.
+func (out *DeprovisionOutput) AddError(err error) {
+	out.Diagnostics.Errors = append(out.Diagnostics.Errors, Error{err.Error()})
+}
+
 // FromHomeDir returns a path with the user's home directory prepended.
 func (in *ProvisionInput) FromHomeDir(path ...string) string {
 	return filepath.Join(append([]string{in.HomeDir}, path...)...)
 }
 
+// ResolvePath returns where to find a path as it's written in config files and environment variables: paths
+// starting with "~/" are relative to the user's home directory. Other paths are returned as they are.
+func (in *ProvisionInput) ResolvePath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return in.FromHomeDir(strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+// Getenv returns the value of the environment variable from Environment, or from the environment of the current
+// process if Environment is nil.
+func (in *ProvisionInput) Getenv(name string) string {
+	if in.Environment == nil {
+		return os.Getenv(name)
+	}
+	return in.Environment[name]
+}
+
 // FromTempDir returns a path with the current execution's temp directory prepended.
 func (in *ProvisionInput) FromTempDir(path ...string) string {
 	return filepath.Join(append([]string{in.TempDir}, path...)...)
 }
 
+// FromTempDir returns a path with the current execution's temp directory prepended.
+func (in *DeprovisionInput) FromTempDir(path ...string) string {
+	return filepath.Join(append([]string{in.TempDir}, path...)...)
+}
+
 // Get returns the cached value at the specified key if it exists. The data can be returned either as a []byte
 // or unmarshaled as JSON.
 func (c CacheState) Get(key string, out any) (ok bool) {
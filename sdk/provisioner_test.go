@@ -122,3 +122,16 @@ func TestCacheOperationsPutStruct(t *testing.T) {
 
 	assert.Equal(t, structData, structResult)
 }
+
+func TestProvisionInputGetenv(t *testing.T) {
+	t.Setenv("SHELL_PLUGINS_TEST_VAR", "from-process")
+
+	in := ProvisionInput{}
+	assert.Equal(t, "from-process", in.Getenv("SHELL_PLUGINS_TEST_VAR"))
+
+	in.Environment = map[string]string{}
+	assert.Equal(t, "", in.Getenv("SHELL_PLUGINS_TEST_VAR"), "an explicit environment should not fall back to the process")
+
+	in.Environment["SHELL_PLUGINS_TEST_VAR"] = "from-input"
+	assert.Equal(t, "from-input", in.Getenv("SHELL_PLUGINS_TEST_VAR"))
+}
@@ -27,6 +27,7 @@ func (e errFunctionFieldNotSet) Error() string {
 type RPCServer struct {
 	p schema.Plugin
 
+	credentials  map[proto.CredentialID]*schema.CredentialType
 	importers    map[proto.CredentialID]sdk.Importer
 	provisioners map[proto.ProvisionerID]sdk.Provisioner
 	needsAuth    map[proto.ExecutableID]sdk.NeedsAuthentication
@@ -34,6 +35,7 @@ type RPCServer struct {
 
 func newServer(p schema.Plugin) *RPCServer {
 	s := &RPCServer{
+		credentials:  map[proto.CredentialID]*schema.CredentialType{},
 		importers:    map[proto.CredentialID]sdk.Importer{},
 		provisioners: map[proto.ProvisionerID]sdk.Provisioner{},
 		needsAuth:    map[proto.ExecutableID]sdk.NeedsAuthentication{},
@@ -58,6 +60,7 @@ func newServer(p schema.Plugin) *RPCServer {
 	}
 
 	for id, c := range credentials {
+		s.credentials[id] = c
 		s.importers[id] = c.Importer
 		c.Importer = nil
 
@@ -117,9 +120,32 @@ func (t *RPCServer) CredentialImport(req proto.ImportCredentialRequest, resp *sd
 		}
 	}
 	importer(context.Background(), req.ImportInput, resp)
+
+	// Flag candidates that don't match the credential schema, e.g. because of a missing prefix. The findings are
+	// reported as errors of the attempt, since that's what the host shows to the user, and name the candidate they
+	// belong to, since an attempt can find multiple candidates.
+	if credential, ok := t.credentials[req.CredentialID]; ok {
+		for _, attempt := range resp.Attempts {
+			for i, candidate := range attempt.Candidates {
+				for _, err := range credential.ValidateFields(candidate.Fields) {
+					attempt.AddError(fmt.Errorf("%s: %w", candidateName(candidate, i), err))
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// candidateName describes the candidate in diagnostics: by its name hint if it has one, or else by its position in
+// the attempt.
+func candidateName(candidate sdk.ImportCandidate, index int) string {
+	if candidate.NameHint != "" {
+		return fmt.Sprintf("candidate '%s'", candidate.NameHint)
+	}
+	return fmt.Sprintf("candidate %d", index+1)
+}
+
 // CredentialProvisionerDescription is a remote version of the the Description() method of the sdk.Provisioner
 // interface. The call is forwarded to the Description() function of the Provisioner of the credential identified by
 // req.CredentialID.
@@ -146,6 +172,22 @@ func (t *RPCServer) CredentialProvisionerProvision(req proto.ProvisionCredential
 		Files:       make(map[string]sdk.OutputFile),
 		Diagnostics: sdk.Diagnostics{},
 	}
+
+	// Rename item fields that go by an alias and check the item against the credential schema before provisioning
+	// it, so that e.g. a token that was pasted without its prefix fails with an error that names the field, instead
+	// of an authentication error from the executable.
+	if credential := t.credentialByName(req.ProvisionerID); credential != nil {
+		req.ItemFields = credential.NormalizeFields(req.ItemFields)
+
+		errs := credential.ValidateFields(req.ItemFields)
+		for _, err := range errs {
+			resp.AddError(err)
+		}
+		if len(errs) > 0 {
+			return nil
+		}
+	}
+
 	provisioner.Provision(context.Background(), req.ProvisionInput, resp)
 	return nil
 }
@@ -165,6 +207,20 @@ func (t *RPCServer) CredentialProvisionerDeprovision(req proto.DeprovisionCreden
 	return nil
 }
 
+// credentialByName returns the schema of the credential the provisioner is for, if it's part of this plugin.
+func (t *RPCServer) credentialByName(provisionerID proto.ProvisionerID) *schema.CredentialType {
+	if provisionerID.Plugin != "" && provisionerID.Plugin != t.p.Name {
+		return nil
+	}
+
+	for _, credential := range t.credentials {
+		if credential.Name == provisionerID.Credential {
+			return credential
+		}
+	}
+	return nil
+}
+
 func (t *RPCServer) getProvisioner(provisionerID proto.ProvisionerID) (sdk.Provisioner, error) {
 	provisioner, ok := t.provisioners[provisionerID]
 	if !ok || provisioner == nil {
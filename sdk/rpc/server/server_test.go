@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/rpc/proto"
+	"github.com/1Password/shell-plugins/sdk/schema"
+	"github.com/1Password/shell-plugins/sdk/schema/fieldname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialImportAttributesFindingsToCandidates(t *testing.T) {
+	s := newServer(schema.Plugin{
+		Name: "example",
+		Credentials: []schema.CredentialType{
+			{
+				Name: "API Key",
+				Fields: []schema.CredentialField{
+					{
+						Name:        fieldname.APIKey,
+						Composition: &schema.ValueComposition{Prefix: "ex_", Charset: schema.Charset{Lowercase: true}},
+					},
+				},
+				Importer: func(ctx context.Context, in sdk.ImportInput, out *sdk.ImportOutput) {
+					attempt := out.NewAttempt(sdk.ImportSource{Files: []string{"~/.example"}})
+					attempt.AddCandidate(sdk.ImportCandidate{NameHint: "valid", Fields: map[sdk.FieldName]string{fieldname.APIKey: "ex_abc"}})
+					attempt.AddCandidate(sdk.ImportCandidate{NameHint: "staging", Fields: map[sdk.FieldName]string{fieldname.APIKey: "abc"}})
+					attempt.AddCandidate(sdk.ImportCandidate{Fields: map[sdk.FieldName]string{fieldname.APIKey: "ex_ABC"}})
+				},
+			},
+		},
+	})
+
+	var out sdk.ImportOutput
+	require.NoError(t, s.CredentialImport(proto.ImportCredentialRequest{CredentialID: 0}, &out))
+
+	require.Len(t, out.Attempts, 1)
+	assert.Len(t, out.Attempts[0].Candidates, 3, "candidates that don't match the schema should still be offered")
+	assert.Equal(t, []sdk.Error{
+		{Message: "candidate 'staging': field 'API Key' should start with 'ex_'"},
+		{Message: "candidate 3: field 'API Key' contains a character that is not allowed at position 4: expected only lowercase letters"},
+	}, out.Errors())
+}
+
+func TestCredentialProvisionerProvisionValidatesItem(t *testing.T) {
+	provisioned := false
+	s := newServer(schema.Plugin{
+		Name: "example",
+		Credentials: []schema.CredentialType{
+			{
+				Name: "API Key",
+				Fields: []schema.CredentialField{
+					{
+						Name:        fieldname.APIKey,
+						Composition: &schema.ValueComposition{Prefix: "ex_", Charset: schema.Charset{Lowercase: true}},
+					},
+					{
+						Name:     fieldname.Region,
+						Optional: true,
+						Aliases:  []sdk.FieldName{"Zone"},
+					},
+				},
+				DefaultProvisioner: provisionerFunc(func(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+					provisioned = true
+					out.AddEnvVar("EXAMPLE_REGION", in.ItemFields[fieldname.Region])
+				}),
+			},
+		},
+	})
+	id := proto.ProvisionerID{Plugin: "example", Credential: "API Key"}
+
+	var out sdk.ProvisionOutput
+	require.NoError(t, s.CredentialProvisionerProvision(proto.ProvisionCredentialRequest{
+		ProvisionerID:  id,
+		ProvisionInput: sdk.ProvisionInput{ItemFields: map[sdk.FieldName]string{fieldname.APIKey: "abc"}},
+	}, &out))
+	assert.Equal(t, []sdk.Error{{Message: "field 'API Key' should start with 'ex_'"}}, out.Diagnostics.Errors)
+	assert.False(t, provisioned, "items that don't match the schema should not be provisioned")
+
+	out = sdk.ProvisionOutput{}
+	require.NoError(t, s.CredentialProvisionerProvision(proto.ProvisionCredentialRequest{
+		ProvisionerID:  id,
+		ProvisionInput: sdk.ProvisionInput{ItemFields: map[sdk.FieldName]string{fieldname.APIKey: "ex_abc", "Zone": "eu"}},
+	}, &out))
+	assert.Empty(t, out.Diagnostics.Errors)
+	assert.Equal(t, map[string]string{"EXAMPLE_REGION": "eu"}, out.Environment)
+}
+
+type provisionerFunc func(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput)
+
+func (f provisionerFunc) Description() string {
+	return "Provision for testing"
+}
+
+func (f provisionerFunc) Provision(ctx context.Context, in sdk.ProvisionInput, out *sdk.ProvisionOutput) {
+	f(ctx, in, out)
+}
+
+func (f provisionerFunc) Deprovision(ctx context.Context, in sdk.DeprovisionInput, out *sdk.DeprovisionOutput) {
+}
@@ -0,0 +1,159 @@
+package scan
+
+import (
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema"
+)
+
+// minVariableLength is the minimum number of characters that should follow the prefix of a value without a fixed
+// length, to not report every occurrence of the prefix itself.
+const minVariableLength = 8
+
+// Detector detects values of a single credential field, based on the value composition of that field.
+type Detector struct {
+	Plugin      string
+	Credential  sdk.CredentialName
+	Field       sdk.FieldName
+	Composition schema.ValueComposition
+}
+
+// NewDetectors compiles the value compositions of the fields of all credential types of the specified plugins into
+// detectors. Fields that don't describe their values well enough to be recognized are skipped: fields without a
+// composition or charset, and fields that have neither a prefix nor a fixed or minimum length.
+func NewDetectors(plugins []schema.Plugin) []Detector {
+	var detectors []Detector
+	for _, plugin := range plugins {
+		for _, credential := range plugin.Credentials {
+			for _, field := range credential.Fields {
+				comp := field.Composition
+				if comp == nil || !hasCharset(comp.Charset) {
+					continue
+				}
+				if len(comp.AllowedPrefixes()) == 0 && comp.Length == 0 && comp.MinLength == 0 {
+					continue
+				}
+
+				detectors = append(detectors, Detector{
+					Plugin:      plugin.Name,
+					Credential:  credential.Name,
+					Field:       field.Name,
+					Composition: *comp,
+				})
+			}
+		}
+	}
+	return detectors
+}
+
+// HasPrefix reports whether the values this detector detects have a fixed prefix. Values with a prefix are much more
+// likely to be actual credentials than values that only happen to have the right length and charset.
+func (d Detector) HasPrefix() bool {
+	return len(d.Composition.AllowedPrefixes()) > 0
+}
+
+// match is a value on a line that matches a detector. The start and end are rune offsets.
+type match struct {
+	start int
+	end   int
+}
+
+// find returns all values on the line that match the detector. A value only matches if it's not part of a longer
+// run of characters from the charset, so a substring of a longer string doesn't get reported. Values also have to
+// match the pattern and checksum of the composition, if set.
+func (d Detector) find(line []rune) []match {
+	var matches []match
+	for start := 0; start < len(line); start++ {
+		if start > 0 && d.continues(line[start-1]) {
+			continue
+		}
+		prefix, ok := d.prefixAt(line[start:])
+		if !ok {
+			continue
+		}
+
+		end := start + len(prefix)
+		for end < len(line) && d.Composition.Charset.Contains(line[end]) {
+			end++
+		}
+
+		if d.lengthMatches(end-start, len(prefix)) && d.validate(string(line[start:end])) {
+			matches = append(matches, match{start: start, end: end})
+		}
+
+		// The characters up to the end of the run can't be the start of another value
+		if end > start {
+			start = end - 1
+		}
+	}
+	return matches
+}
+
+// prefixAt returns the longest allowed prefix the value starts with. Values always match if there are no prefixes.
+func (d Detector) prefixAt(value []rune) ([]rune, bool) {
+	prefixes := d.Composition.AllowedPrefixes()
+	if len(prefixes) == 0 {
+		return nil, true
+	}
+
+	var longest []rune
+	ok := false
+	for _, prefix := range prefixes {
+		runes := []rune(prefix)
+		if hasRunePrefix(value, runes) && len(runes) >= len(longest) {
+			longest = runes
+			ok = true
+		}
+	}
+	return longest, ok
+}
+
+func (d Detector) lengthMatches(length int, prefixLength int) bool {
+	comp := d.Composition
+	switch {
+	case comp.Length > 0:
+		return length == comp.Length
+	case comp.MinLength > 0:
+		return length >= comp.MinLength && (comp.MaxLength == 0 || length <= comp.MaxLength)
+	default:
+		return length-prefixLength >= minVariableLength && (comp.MaxLength == 0 || length <= comp.MaxLength)
+	}
+}
+
+// validate checks the value against the whole composition, which includes the pattern and checksum.
+func (d Detector) validate(value string) bool {
+	field := schema.CredentialField{Name: d.Field, Composition: &d.Composition}
+	return field.ValidateValue(value) == nil
+}
+
+// continues reports whether a value that is directly preceded by the specified character would be part of a longer
+// value, instead of standing on its own.
+func (d Detector) continues(r rune) bool {
+	if d.Composition.Charset.Contains(r) {
+		return true
+	}
+	// Prefixes can contain characters that are not part of the charset, like the "-" in "glpat-"
+	for _, prefix := range d.Composition.AllowedPrefixes() {
+		if strings.ContainsRune(prefix, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRunePrefix(s []rune, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func hasCharset(c schema.Charset) bool {
+	return c.Uppercase || c.Lowercase || c.Digits || c.Symbols || len(c.Specific) > 0
+}
@@ -0,0 +1,77 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ScanDiff scans the lines that are added in a unified diff, such as the output of `git diff`. Removed and context
+// lines are skipped, so only credentials that a change introduces get reported. The findings refer to the paths and
+// line numbers in the new version of the files.
+func (s *Scanner) ScanDiff(r io.Reader) ([]Finding, error) {
+	var findings []Finding
+	var path string
+	var lineNumber int
+	inHeader := false
+
+	lineScanner := newLineScanner(r)
+	for lineScanner.Scan() {
+		line := lineScanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			inHeader = true
+			path = ""
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			path = diffPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			start, err := hunkStart(line)
+			if err != nil {
+				return nil, err
+			}
+			lineNumber = start
+		case inHeader:
+			// Other header lines, like the index line or file mode changes
+		case strings.HasPrefix(line, "+"):
+			if path != "" {
+				findings = append(findings, s.scanLine(path, lineNumber, strings.TrimPrefix(line, "+"))...)
+			}
+			lineNumber++
+		case strings.HasPrefix(line, " "):
+			lineNumber++
+		}
+	}
+
+	return findings, lineScanner.Err()
+}
+
+// diffPath returns the path of the new version of the file from a "+++" line, or an empty string if the file is
+// deleted.
+func diffPath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		path = unquoted
+	}
+	return strings.TrimPrefix(path, "b/")
+}
+
+// hunkStart returns the line number of the first line of a hunk in the new version of the file, from a hunk header
+// like "@@ -1,4 +1,5 @@".
+func hunkStart(header string) (int, error) {
+	fields := strings.Fields(header)
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "+") {
+			continue
+		}
+
+		start, _, _ := strings.Cut(strings.TrimPrefix(field, "+"), ",")
+		return strconv.Atoi(start)
+	}
+	return 0, fmt.Errorf("invalid hunk header: %s", header)
+}
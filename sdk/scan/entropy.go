@@ -0,0 +1,33 @@
+package scan
+
+import "math"
+
+// DefaultMinEntropy is the default minimum Shannon entropy, in bits per character, that a value without a prefix
+// should have to be reported. Random tokens score well above this, while identifiers, words and repeated
+// characters that happen to have the right length and charset mostly score below it.
+const DefaultMinEntropy = 3.0
+
+// Entropy returns the Shannon entropy of the value in bits per character.
+func Entropy(value string) float64 {
+	// The counts are summed up in order of first occurrence, so the result doesn't depend on map iteration order
+	indexes := make(map[rune]int)
+	var counts []int
+	total := 0
+	for _, r := range value {
+		i, ok := indexes[r]
+		if !ok {
+			i = len(counts)
+			indexes[r] = i
+			counts = append(counts, 0)
+		}
+		counts[i]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,210 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/1Password/shell-plugins/sdk/schema"
+)
+
+// Files larger than this are skipped, since credentials are unlikely to be found in them.
+const maxFileSize = 10 * 1024 * 1024
+
+// Scanner scans files for values that look like credentials of the registered plugins.
+type Scanner struct {
+	Detectors []Detector
+
+	// The minimum entropy that values without a prefix should have to be reported. Values with a prefix are always
+	// reported, since the prefix alone makes them likely to be a credential.
+	MinEntropy float64
+}
+
+// Finding is a value that looks like a credential. The value itself is never part of the finding, only a redacted
+// version of it, so findings can be printed safely.
+type Finding struct {
+	Path   string
+	Line   int
+	Column int
+
+	// The value with all but the prefix or the first few characters masked.
+	Redacted string
+
+	// The Shannon entropy of the value in bits per character.
+	Entropy float64
+
+	// The fields the value could belong to, the most likely one first.
+	Candidates []Candidate
+}
+
+// Candidate is a credential field a finding could belong to.
+type Candidate struct {
+	Plugin     string
+	Credential sdk.CredentialName
+	Field      sdk.FieldName
+
+	// Whether the value matched on the prefix of the field.
+	Prefixed bool
+}
+
+// Likely returns the credential field the finding most likely belongs to.
+func (f Finding) Likely() Candidate {
+	return f.Candidates[0]
+}
+
+// NewScanner returns a scanner with detectors for all credential fields of the specified plugins.
+func NewScanner(plugins []schema.Plugin) *Scanner {
+	return &Scanner{
+		Detectors:  NewDetectors(plugins),
+		MinEntropy: DefaultMinEntropy,
+	}
+}
+
+// ScanPath scans the file at the specified path, or all files in it recursively if it's a directory. Git
+// directories and binary files are skipped.
+func (s *Scanner) ScanPath(path string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileSize {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if isBinary(contents) {
+			return nil
+		}
+
+		fileFindings, err := s.Scan(path, bytes.NewReader(contents))
+		if err != nil {
+			return err
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	return findings, err
+}
+
+// Scan scans the contents of the reader line by line. The path is only used to fill in the findings.
+func (s *Scanner) Scan(path string, r io.Reader) ([]Finding, error) {
+	var findings []Finding
+	lineScanner := newLineScanner(r)
+	for lineNumber := 1; lineScanner.Scan(); lineNumber++ {
+		findings = append(findings, s.scanLine(path, lineNumber, lineScanner.Text())...)
+	}
+	return findings, lineScanner.Err()
+}
+
+// scanLine returns the findings on a single line. Values matched by multiple detectors result in a single finding
+// with multiple candidates.
+func (s *Scanner) scanLine(path string, lineNumber int, line string) []Finding {
+	runes := []rune(line)
+
+	var findings []Finding
+	byMatch := make(map[match]int)
+	for _, detector := range s.Detectors {
+		for _, m := range detector.find(runes) {
+			value := string(runes[m.start:m.end])
+			prefix, _ := detector.prefixAt(runes[m.start:m.end])
+			entropy := Entropy(value)
+			if !detector.HasPrefix() && entropy < s.MinEntropy {
+				continue
+			}
+
+			candidate := Candidate{
+				Plugin:     detector.Plugin,
+				Credential: detector.Credential,
+				Field:      detector.Field,
+				Prefixed:   detector.HasPrefix(),
+			}
+
+			if i, ok := byMatch[m]; ok {
+				findings[i].Candidates = append(findings[i].Candidates, candidate)
+				continue
+			}
+
+			byMatch[m] = len(findings)
+			findings = append(findings, Finding{
+				Path:       path,
+				Line:       lineNumber,
+				Column:     m.start + 1,
+				Redacted:   redact(value, string(prefix)),
+				Entropy:    entropy,
+				Candidates: []Candidate{candidate},
+			})
+		}
+	}
+
+	for _, finding := range findings {
+		sortCandidates(finding.Candidates)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// sortCandidates puts the candidates that matched on a prefix first, since those are the most likely ones.
+func sortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Prefixed != candidates[j].Prefixed {
+			return candidates[i].Prefixed
+		}
+		return candidates[i].Plugin < candidates[j].Plugin
+	})
+}
+
+// redact masks the value, except for the prefix or the first 4 characters if there's no prefix.
+func redact(value string, prefix string) string {
+	visible := len([]rune(prefix))
+	if visible == 0 {
+		visible = 4
+	}
+
+	runes := []rune(value)
+	if visible > len(runes)/2 {
+		visible = len(runes) / 2
+	}
+	return string(runes[:visible]) + strings.Repeat("*", len(runes)-visible)
+}
+
+// isBinary reports whether the contents look like binary data, using the same heuristic as git: the presence of a
+// NUL byte in the first 8000 bytes.
+func isBinary(contents []byte) bool {
+	if len(contents) > 8000 {
+		contents = contents[:8000]
+	}
+	return bytes.IndexByte(contents, 0) != -1
+}
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// Minified files and lockfiles can have very long lines
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	return scanner
+}
@@ -44,28 +44,111 @@ type CredentialField struct {
 
 	// (Optional) Describes how values of this field look like, such as the length, charset, etc.
 	Composition *ValueComposition
+
+	// (Optional) What kind of value this field holds, e.g. a URL or a port. Defaults to FieldTypeString.
+	Type FieldType
+
+	// (Optional) The value to suggest for this field when the user creates an item, e.g. "github.com". It's only a
+	// hint for the UI: items that don't have the field set are provisioned without it, so provisioners should handle
+	// a missing value themselves. Can't be set on secret fields.
+	Default string
+
+	// (Optional) An example value to show in an empty input for this field, e.g. "https://example.okta.com".
+	Placeholder string
+
+	// (Optional) The values this field is limited to, e.g. "live" and "test".
+	Enum []string
+
+	// (Optional) Other names this field can go by, e.g. "Region" for "Default Region". Item fields with one of
+	// these names are treated as this field, if the item doesn't have a field with the name itself.
+	Aliases []sdk.FieldName
 }
 
+// Field returns the field with the specified name or alias, or nil if the credential type has no such field.
 func (c CredentialType) Field(name string) *CredentialField {
 	for _, field := range c.Fields {
 		if field.Name.String() == name {
 			return &field
 		}
 	}
+	for _, field := range c.Fields {
+		for _, alias := range field.Aliases {
+			if alias.String() == name {
+				return &field
+			}
+		}
+	}
 	return nil
 }
 
+// NormalizeFields returns a copy of the item fields in which fields that are named after an alias are renamed to
+// the name of the field they're an alias for. Fields that are already set under their own name are not overwritten.
+func (c CredentialType) NormalizeFields(fields map[sdk.FieldName]string) map[sdk.FieldName]string {
+	normalized := make(map[sdk.FieldName]string, len(fields))
+	for name, value := range fields {
+		normalized[name] = value
+	}
+
+	for _, field := range c.Fields {
+		if _, ok := normalized[field.Name]; ok {
+			continue
+		}
+		for _, alias := range field.Aliases {
+			if value, ok := normalized[alias]; ok {
+				normalized[field.Name] = value
+				delete(normalized, alias)
+				break
+			}
+		}
+	}
+	return normalized
+}
+
 // ValueComposition describes what a value for a certain field looks like. This gets used for various purposes,
 // including but not limited to the Save in 1Password functionality and secrets scanning functionality.
 type ValueComposition struct {
 	// The length of the value, if it's guaranteed to be of a fixed length.
 	Length int
 
+	// (Optional) The minimum and maximum length of the value, if it's of a variable length. Can't be combined with
+	// Length. A maximum length of 0 means there's no maximum.
+	MinLength int
+	MaxLength int
+
 	// Which characters the value can consist of.
 	Charset Charset
 
 	// (Optional) A certain prefix that's always present on the value, as popularized by GitHub.
 	Prefix string
+
+	// (Optional) The prefixes of which one is always present on the value, if the value can come with different
+	// prefixes, e.g. "sk_live_" and "sk_test_". Can't be combined with Prefix.
+	Prefixes []string
+
+	// (Optional) A regular expression that the whole value should match, to describe formats that can't be described
+	// by the other properties alone, e.g. a value that consists of multiple parts.
+	Pattern string
+
+	// (Optional) Validates the checksum that's embedded in the value, e.g. the CRC32 checksum in GitHub tokens.
+	Checksum ChecksumValidator
+}
+
+// ChecksumValidator validates and generates the checksum that's embedded in values of a certain format.
+type ChecksumValidator interface {
+	// ValidateChecksum returns an error if the value contains a checksum that doesn't match the rest of the value.
+	ValidateChecksum(value string) error
+
+	// AddChecksum returns the value with its checksum part replaced by the checksum of the rest of the value. This is
+	// used to generate example values.
+	AddChecksum(value string) string
+}
+
+// AllowedPrefixes returns all prefixes that values can start with, either from Prefix or Prefixes.
+func (v ValueComposition) AllowedPrefixes() []string {
+	if v.Prefix != "" {
+		return []string{v.Prefix}
+	}
+	return v.Prefixes
 }
 
 type Charset struct {
@@ -83,30 +166,35 @@ func (c CredentialType) Validate() (bool, ValidationReport) {
 	}
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-name",
 		Description: "Has name set",
 		Assertion:   c.Name != "",
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-name-title-case",
 		Description: "Name is using title case",
 		Assertion:   IsTitleCaseString(c.Name.String()),
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-docs-url",
 		Description: "Has documentation URL set",
 		Assertion:   c.DocsURL != nil,
 		Severity:    ValidationSeverityWarning,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-management-url",
 		Description: "Has management URL set",
 		Assertion:   c.ManagementURL != nil,
 		Severity:    ValidationSeverityWarning,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-fields",
 		Description: "Has at least 1 field",
 		Assertion:   len(c.Fields) > 0,
 		Severity:    ValidationSeverityError,
@@ -115,7 +203,7 @@ func (c CredentialType) Validate() (bool, ValidationReport) {
 	allFieldsHaveNameSet := true
 	allFieldsHaveDescriptionSet := true
 	allFieldsInTitleCase := true
-	allCompositionsValid := true
+	allMetadataValid := true
 	hasSecretField := false
 	for _, f := range c.Fields {
 		if f.Name == "" {
@@ -127,12 +215,8 @@ func (c CredentialType) Validate() (bool, ValidationReport) {
 		if !IsTitleCaseString(f.Name.String()) {
 			allFieldsInTitleCase = false
 		}
-		comp := f.Composition
-		if comp != nil {
-			cs := comp.Charset
-			if !cs.Lowercase && !cs.Uppercase && !cs.Digits && !cs.Symbols && len(cs.Specific) == 0 {
-				allCompositionsValid = false
-			}
+		if f.validateMetadata() != nil {
+			allMetadataValid = false
 		}
 		if f.Secret {
 			hasSecretField = true
@@ -140,42 +224,74 @@ func (c CredentialType) Validate() (bool, ValidationReport) {
 	}
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-field-names",
 		Description: "All fields have name set",
 		Assertion:   allFieldsHaveNameSet,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-field-names-title-case",
 		Description: "All field names are using title case",
 		Assertion:   allFieldsInTitleCase,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-field-descriptions",
 		Description: "All fields have a description set",
 		Assertion:   allFieldsHaveDescriptionSet,
 		Severity:    ValidationSeverityError,
 	})
 
+	for _, f := range c.Fields {
+		if f.Composition == nil {
+			continue
+		}
+		description := fmt.Sprintf("Value composition of field '%s' is valid", f.Name)
+		err := f.Composition.validateDefinition()
+		if err != nil {
+			description += fmt.Sprintf(": %s", err)
+		}
+		report.AddCheck(ValidationCheck{
+			ID:          "credential-field-compositions",
+			Subject:     f.Name.String(),
+			Description: description,
+			Assertion:   err == nil,
+			Severity:    ValidationSeverityError,
+		})
+	}
+
+	report.AddCheck(ValidationCheck{
+		ID:          "credential-field-metadata",
+		Description: "All field types, default values, placeholders and enum values are valid",
+		Assertion:   allMetadataValid,
+		Severity:    ValidationSeverityError,
+	})
+
 	report.AddCheck(ValidationCheck{
-		Description: "All specified value compositions are valid",
-		Assertion:   allCompositionsValid,
+		ID:          "credential-field-aliases",
+		Description: "All field aliases are unique and don't clash with field names",
+		Assertion:   c.hasUniqueFieldNames(),
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-secret-field",
 		Description: "Has at least 1 field that is secret",
 		Assertion:   hasSecretField,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-provisioner",
 		Description: "Has a provisioner set",
 		Assertion:   c.DefaultProvisioner != nil,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "credential-importer",
 		Description: "Has an importer set",
 		Assertion:   c.Importer != nil,
 		Severity:    ValidationSeverityWarning,
@@ -183,3 +299,17 @@ func (c CredentialType) Validate() (bool, ValidationReport) {
 
 	return report.IsValid(), report
 }
+
+// hasUniqueFieldNames reports whether no two fields share a name or alias.
+func (c CredentialType) hasUniqueFieldNames() bool {
+	seen := make(map[sdk.FieldName]bool)
+	for _, f := range c.Fields {
+		for _, name := range append([]sdk.FieldName{f.Name}, f.Aliases...) {
+			if seen[name] {
+				return false
+			}
+			seen[name] = true
+		}
+	}
+	return true
+}
@@ -8,6 +8,7 @@ const (
 	APIToken            = sdk.CredentialName("API Token")
 	AccessKey           = sdk.CredentialName("Access Key")
 	AccessToken         = sdk.CredentialName("Access Token")
+	AppCredentials      = sdk.CredentialName("App Credentials")
 	AppPassword         = sdk.CredentialName("App Password")
 	AppToken            = sdk.CredentialName("App Token")
 	AuthToken           = sdk.CredentialName("Auth Token")
@@ -27,6 +28,7 @@ func ListAll() []sdk.CredentialName {
 		APIToken,
 		AccessKey,
 		AccessToken,
+		AppCredentials,
 		AppPassword,
 		AppToken,
 		AuthToken,
@@ -37,6 +37,11 @@ type CredentialUsage struct {
 	// set in the credential schema, so should only be used if this executable requires a custom configuration, that deviates
 	// from the way the credential is usually provisioned.
 	Provisioner sdk.Provisioner
+
+	// (Optional) Whether the executable can run without an item for this credential. Credentials that are
+	// alternative ways to authenticate, e.g. with a personal access token or as a GitHub App, are all marked as
+	// optional, so that only one of them has to be set up.
+	Optional bool
 }
 
 func (e Executable) Validate() (bool, ValidationReport) {
@@ -46,30 +51,35 @@ func (e Executable) Validate() (bool, ValidationReport) {
 	}
 
 	report.AddCheck(ValidationCheck{
+		ID:          "executable-name",
 		Description: "Has name set",
 		Assertion:   e.Name != "",
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "executable-docs-url",
 		Description: "Has documentation URL set",
 		Assertion:   e.DocsURL != nil,
 		Severity:    ValidationSeverityWarning,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "executable-needs-auth",
 		Description: "Has specified which commands need authentication",
 		Assertion:   e.NeedsAuth != nil,
 		Severity:    ValidationSeverityWarning,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "executable-command",
 		Description: "Has executable command set",
 		Assertion:   len(e.Runs) > 0,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "executable-credentials",
 		Description: "Has a credential type defined",
 		Assertion:   len(e.Uses) > 0,
 		Severity:    ValidationSeverityError,
@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/1Password/shell-plugins/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValueFieldType(t *testing.T) {
+	cases := map[string]struct {
+		fieldType FieldType
+		value     string
+		expected  string
+	}{
+		"when URL is valid": {
+			fieldType: FieldTypeURL,
+			value:     "https://example.okta.com",
+		},
+		"when URL has no scheme": {
+			fieldType: FieldTypeURL,
+			value:     "example.okta.com",
+			expected:  "field 'Example' should be an absolute URL, like 'https://example.com'",
+		},
+		"when hostname is valid": {
+			fieldType: FieldTypeHostname,
+			value:     "github.example.com",
+		},
+		"when hostname is an IP address": {
+			fieldType: FieldTypeHostname,
+			value:     "::1",
+		},
+		"when hostname has a scheme": {
+			fieldType: FieldTypeHostname,
+			value:     "https://github.example.com",
+			expected:  "field 'Example' should be a hostname or IP address, without a scheme or port",
+		},
+		"when port is valid": {
+			fieldType: FieldTypePort,
+			value:     "5432",
+		},
+		"when port is out of range": {
+			fieldType: FieldTypePort,
+			value:     "65536",
+			expected:  "field 'Example' should be a port number between 1 and 65535",
+		},
+		"when PEM is valid": {
+			fieldType: FieldTypePEM,
+			value:     "-----BEGIN CERTIFICATE-----\nZXhhbXBsZQ==\n-----END CERTIFICATE-----",
+		},
+		"when PEM has no header": {
+			fieldType: FieldTypePEM,
+			value:     "ZXhhbXBsZQ==",
+			expected:  "field 'Example' should be PEM-encoded, starting with a '-----BEGIN' line",
+		},
+		"when OTP is valid": {
+			fieldType: FieldTypeOTP,
+			value:     "123456",
+		},
+		"when OTP is too short": {
+			fieldType: FieldTypeOTP,
+			value:     "12345",
+			expected:  "field 'Example' should be a one-time code of 6 to 8 digits",
+		},
+		"when TOTP is an otpauth URI": {
+			fieldType: FieldTypeTOTP,
+			value:     "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP",
+		},
+		"when TOTP is a code": {
+			fieldType: FieldTypeTOTP,
+			value:     "123456",
+		},
+		"when TOTP secret is too short": {
+			fieldType: FieldTypeTOTP,
+			value:     "JBSWY3DP",
+			expected:  "field 'Example' should be an otpauth:// URI, a base32 encoded secret or a one-time code: secret should be at least 80 bits",
+		},
+		"when type is a string": {
+			fieldType: FieldTypeString,
+			value:     "anything goes",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			field := CredentialField{Name: "Example", Type: tc.fieldType}
+			err := field.ValidateValue(tc.value)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFieldTypeExamplesAreValid(t *testing.T) {
+	for _, fieldType := range FieldTypes() {
+		field := CredentialField{Name: "Example", Type: fieldType, Optional: true}
+		assert.NoError(t, field.ValidateValue(fieldType.Example()), fieldType)
+	}
+}
+
+func TestValidateValueEnum(t *testing.T) {
+	field := CredentialField{Name: "Mode", Optional: true, Enum: []string{"live", "test"}}
+
+	assert.NoError(t, field.ValidateValue("test"))
+	assert.NoError(t, field.ValidateValue(""))
+	assert.EqualError(t, field.ValidateValue("Live"), "field 'Mode' should be one of 'live', 'test'")
+}
+
+func TestFieldMetadata(t *testing.T) {
+	cases := map[string]struct {
+		field    CredentialField
+		expected string
+	}{
+		"when metadata is valid": {
+			field: CredentialField{
+				Name:        "Port",
+				Type:        FieldTypePort,
+				Default:     "5432",
+				Placeholder: "5432",
+				Enum:        []string{"5432", "5433"},
+			},
+		},
+		"when type is unknown": {
+			field:    CredentialField{Name: "Port", Type: "number"},
+			expected: "unknown type 'number'",
+		},
+		"when default is not of the type": {
+			field:    CredentialField{Name: "Port", Type: FieldTypePort, Default: "postgres"},
+			expected: "default value is invalid: field 'Port' should be a port number between 1 and 65535",
+		},
+		"when default is not an enum value": {
+			field:    CredentialField{Name: "Mode", Default: "staging", Enum: []string{"live", "test"}},
+			expected: "default value is invalid: field 'Mode' should be one of 'live', 'test'",
+		},
+		"when secret field has a default": {
+			field:    CredentialField{Name: "Password", Secret: true, Default: "hunter2"},
+			expected: "secret fields can't have a default value",
+		},
+		"when enum value is listed twice": {
+			field:    CredentialField{Name: "Mode", Enum: []string{"live", "test", "live"}},
+			expected: "enum value 'live' is listed more than once",
+		},
+		"when enum value doesn't match the composition": {
+			field: CredentialField{
+				Name:        "Mode",
+				Enum:        []string{"live", "TEST"},
+				Composition: &ValueComposition{Charset: Charset{Lowercase: true}},
+			},
+			expected: "enum value 'TEST' is invalid: field 'Mode' contains a character that is not allowed at position 1: expected only lowercase letters",
+		},
+		"when placeholder doesn't match the composition": {
+			field: CredentialField{
+				Name:        "Token",
+				Secret:      true,
+				Placeholder: "ghp_...",
+				Composition: &ValueComposition{Prefix: "ghp_", Length: 40, Charset: Charset{Lowercase: true}},
+			},
+		},
+		"when placeholder is not of the type": {
+			field:    CredentialField{Name: "URL", Type: FieldTypeURL, Placeholder: "example.okta.com"},
+			expected: "placeholder is invalid: field 'URL' should be an absolute URL, like 'https://example.com'",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.field.validateMetadata()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFieldAliases(t *testing.T) {
+	credential := CredentialType{
+		Fields: []CredentialField{
+			{Name: "Default Region", Aliases: []sdk.FieldName{"Region", "AWS Region"}},
+			{Name: "Profile"},
+		},
+	}
+
+	field := credential.Field("AWS Region")
+	if assert.NotNil(t, field) {
+		assert.Equal(t, sdk.FieldName("Default Region"), field.Name)
+	}
+	assert.True(t, credential.hasUniqueFieldNames())
+
+	normalized := credential.NormalizeFields(map[sdk.FieldName]string{
+		"Region":  "eu-west-1",
+		"Profile": "dev",
+	})
+	assert.Equal(t, map[sdk.FieldName]string{
+		"Default Region": "eu-west-1",
+		"Profile":        "dev",
+	}, normalized)
+
+	normalized = credential.NormalizeFields(map[sdk.FieldName]string{
+		"Default Region": "us-east-1",
+		"Region":         "eu-west-1",
+	})
+	assert.Equal(t, "us-east-1", normalized["Default Region"], "fields set under their own name should not be overwritten")
+
+	credential.Fields = append(credential.Fields, CredentialField{Name: "Region"})
+	assert.False(t, credential.hasUniqueFieldNames())
+}
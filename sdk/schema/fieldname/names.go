@@ -13,6 +13,7 @@ const (
 	AccountID       = sdk.FieldName("Account ID")
 	AccountSID      = sdk.FieldName("Account SID")
 	Address         = sdk.FieldName("Address")
+	AppID           = sdk.FieldName("App ID")
 	AppKey          = sdk.FieldName("App Key")
 	AppSecret       = sdk.FieldName("App Secret")
 	AppToken        = sdk.FieldName("App Token")
@@ -23,8 +24,11 @@ const (
 	Credentials     = sdk.FieldName("Credentials")
 	Database        = sdk.FieldName("Database")
 	DefaultRegion   = sdk.FieldName("Default Region")
+	Duration        = sdk.FieldName("Duration")
+	ExternalID      = sdk.FieldName("External ID")
 	Host            = sdk.FieldName("Host")
 	HostAddress     = sdk.FieldName("Host Address")
+	InstallationID  = sdk.FieldName("Installation ID")
 	Key             = sdk.FieldName("Key")
 	MFASerial       = sdk.FieldName("MFA Serial")
 	Mode            = sdk.FieldName("Mode")
@@ -35,9 +39,15 @@ const (
 	Password        = sdk.FieldName("Password")
 	Port            = sdk.FieldName("Port")
 	PrivateKey      = sdk.FieldName("Private Key")
+	Profile         = sdk.FieldName("Profile")
+	Project         = sdk.FieldName("Project")
 	Region          = sdk.FieldName("Region")
+	RoleARN         = sdk.FieldName("Role ARN")
+	RoleID          = sdk.FieldName("Role ID")
 	Secret          = sdk.FieldName("Secret")
 	SecretAccessKey = sdk.FieldName("Secret Access Key")
+	SecretID        = sdk.FieldName("Secret ID")
+	SessionName     = sdk.FieldName("Session Name")
 	Token           = sdk.FieldName("Token")
 	URL             = sdk.FieldName("URL")
 	User            = sdk.FieldName("User")
@@ -49,12 +59,14 @@ func ListAll() []sdk.FieldName {
 	return []sdk.FieldName{
 		APIHost,
 		APIKey,
+		APIKeyID,
 		APISecret,
 		AccessKeyID,
 		Account,
 		AccountID,
 		AccountSID,
 		Address,
+		AppID,
 		AppKey,
 		AppSecret,
 		AppToken,
@@ -65,8 +77,11 @@ func ListAll() []sdk.FieldName {
 		Credentials,
 		Database,
 		DefaultRegion,
+		Duration,
+		ExternalID,
 		Host,
 		HostAddress,
+		InstallationID,
 		Key,
 		MFASerial,
 		Mode,
@@ -77,9 +92,15 @@ func ListAll() []sdk.FieldName {
 		Password,
 		Port,
 		PrivateKey,
+		Profile,
+		Project,
 		Region,
+		RoleARN,
+		RoleID,
 		Secret,
 		SecretAccessKey,
+		SecretID,
+		SessionName,
 		Token,
 		URL,
 		User,
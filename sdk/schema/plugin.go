@@ -37,48 +37,56 @@ func (p Plugin) Validate() (bool, ValidationReport) {
 	}
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-name",
 		Description: "Has plugin name set",
 		Assertion:   p.Name != "",
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-name-format",
 		Description: "Plugin name only using lowercase characters or digits",
 		Assertion:   ContainsLowercaseLettersOrDigits(p.Name),
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-name-length",
 		Description: "Plugin name not longer than 20 characters",
 		Assertion:   len(p.Name) <= 20,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-platform-name",
 		Description: "Has platform name set",
 		Assertion:   p.Platform.Name != "",
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-platform-homepage",
 		Description: "Has platform homepage URL set",
 		Assertion:   p.Platform.Homepage != nil,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-credentials-and-executables",
 		Description: "Has a credential type or executable defined",
 		Assertion:   len(p.Credentials) > 0 && len(p.Executables) > 0,
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
-		Description: "Has no more than one credential type defined. Plugins with multiple credential types are not supported yet",
-		Assertion:   len(p.Credentials) == 1,
+		ID:          "plugin-single-credential",
+		Description: "Has no more than one credential type defined, unless the executables use them as optional alternatives. Plugins with multiple required credential types are not supported yet",
+		Assertion:   len(p.Credentials) == 1 || p.credentialsAreAlternatives(),
 		Severity:    ValidationSeverityError,
 	})
 
 	report.AddCheck(ValidationCheck{
+		ID:          "plugin-single-executable",
 		Description: "Has no more than one executable defined. Plugins with multiple executables are not supported yet",
 		Assertion:   len(p.Executables) == 1,
 		Severity:    ValidationSeverityError,
@@ -87,6 +95,29 @@ func (p Plugin) Validate() (bool, ValidationReport) {
 	return report.IsValid(), report
 }
 
+// credentialsAreAlternatives reports whether every credential type of the plugin is used by the executables, and
+// only as an optional alternative to the others.
+func (p Plugin) credentialsAreAlternatives() bool {
+	for _, cred := range p.Credentials {
+		used := false
+		for _, exe := range p.Executables {
+			for _, usage := range exe.Uses {
+				if usage.Name != cred.Name || (usage.Plugin != "" && usage.Plugin != p.Name) {
+					continue
+				}
+				if !usage.Optional {
+					return false
+				}
+				used = true
+			}
+		}
+		if !used {
+			return false
+		}
+	}
+	return true
+}
+
 func (p Plugin) DeepValidate() []ValidationReport {
 	var reports []ValidationReport
 
@@ -18,7 +18,7 @@ func (vr *ValidationReport) IsValid() bool {
 	isValid := true
 
 	for _, check := range vr.Checks {
-		if !check.Assertion {
+		if !check.Assertion && !check.IsSuppressed() {
 			isValid = false
 			break
 		}
@@ -29,7 +29,7 @@ func (vr *ValidationReport) IsValid() bool {
 
 func (vr *ValidationReport) HasErrors() bool {
 	for _, check := range vr.Checks {
-		if !check.Assertion && check.Severity == ValidationSeverityError {
+		if !check.Assertion && !check.IsSuppressed() && check.Severity == ValidationSeverityError {
 			return true
 		}
 	}
@@ -38,12 +38,25 @@ func (vr *ValidationReport) HasErrors() bool {
 }
 
 type ValidationCheck struct {
+	// ID identifies the kind of check, e.g. "credential-docs-url". It stays the same across releases, so it can be used
+	// to refer to the check from tools. Checks that are run for every field, executable, etc. share the same ID.
+	ID string
+	// Subject is the field, executable, environment variable, etc. the check is about, for checks that are run for
+	// each of them, e.g. "Token". Together with the ID, it identifies a single check within a report.
+	Subject string
 	// Description explains what we want to validate
 	Description string
 	// Assertion
 	Assertion bool
 	// Severity is "warning" for Optional fields that are not passed and "error" for Required fields
 	Severity ValidationSeverity
+	// Suppression is the reason a failed check is waived, if it is. Suppressed checks don't count as warnings or errors.
+	Suppression string
+}
+
+// IsSuppressed reports whether the check is waived.
+func (c ValidationCheck) IsSuppressed() bool {
+	return c.Suppression != ""
 }
 
 type ValidationSeverity string
@@ -135,3 +135,38 @@ func TestPluginValidateEachReportFieldHasError(t *testing.T) {
 
 	assert.False(t, c.Assertion, fmt.Sprintf("\"%s\" validation is erroneous", c.Description))
 }
+
+func TestPluginValidateMultipleCredentials(t *testing.T) {
+	plugin := func(optional bool) Plugin {
+		return Plugin{
+			Name:        "example",
+			Credentials: []CredentialType{{Name: "Personal Access Token"}, {Name: "App Credentials"}},
+			Executables: []Executable{
+				{
+					Name: "Example CLI",
+					Uses: []CredentialUsage{
+						{Name: "Personal Access Token", Optional: optional},
+						{Name: "App Credentials", Optional: optional},
+					},
+				},
+			},
+		}
+	}
+
+	for description, scenario := range map[string]struct {
+		plugin   Plugin
+		expected bool
+	}{
+		"required credentials":    {plugin: plugin(false), expected: false},
+		"alternative credentials": {plugin: plugin(true), expected: true},
+	} {
+		t.Run(description, func(t *testing.T) {
+			_, report := scenario.plugin.Validate()
+			for _, check := range report.Checks {
+				if check.ID == "plugin-single-credential" {
+					assert.Equal(t, scenario.expected, check.Assertion)
+				}
+			}
+		})
+	}
+}
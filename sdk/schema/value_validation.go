@@ -0,0 +1,245 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/1Password/shell-plugins/sdk"
+)
+
+const symbolCharacters = "~!@#$%^&*()-_+={}[]\\|<,>.?/\"';:`"
+
+// ValidateValue checks whether the value is one of the field's enum values and of the field's type, if set, and
+// whether it matches the field's value composition, if set: the prefix, length, charset, pattern and checksum.
+// Optional fields may be empty. The returned error never contains the value itself, since it can be secret.
+func (f CredentialField) ValidateValue(value string) error {
+	if value == "" {
+		if f.Optional {
+			return nil
+		}
+		return fmt.Errorf("field '%s' is required but has no value", f.Name)
+	}
+
+	if err := f.validateFormat(value); err != nil {
+		return err
+	}
+
+	comp := f.Composition
+	if comp == nil {
+		return nil
+	}
+
+	prefixes := comp.AllowedPrefixes()
+	prefix, ok := matchingPrefix(value, prefixes)
+	if !ok {
+		if len(prefixes) == 1 {
+			return fmt.Errorf("field '%s' should start with '%s'", f.Name, prefixes[0])
+		}
+		return fmt.Errorf("field '%s' should start with one of '%s'", f.Name, strings.Join(prefixes, "', '"))
+	}
+
+	length := utf8.RuneCountInString(value)
+	switch {
+	case comp.Length > 0 && length != comp.Length:
+		return fmt.Errorf("field '%s' should be %d characters long, but is %d characters long", f.Name, comp.Length, length)
+	case comp.MinLength > 0 && length < comp.MinLength:
+		return fmt.Errorf("field '%s' should be at least %d characters long, but is %d characters long", f.Name, comp.MinLength, length)
+	case comp.MaxLength > 0 && length > comp.MaxLength:
+		return fmt.Errorf("field '%s' should be at most %d characters long, but is %d characters long", f.Name, comp.MaxLength, length)
+	}
+
+	// The prefix doesn't have to consist of characters from the charset, e.g. the "-" in "glpat-"
+	if comp.Charset.isSet() {
+		for i, r := range []rune(strings.TrimPrefix(value, prefix)) {
+			if !comp.Charset.Contains(r) {
+				return fmt.Errorf("field '%s' contains a character that is not allowed at position %d: expected only %s", f.Name, utf8.RuneCountInString(prefix)+i+1, comp.Charset.describe())
+			}
+		}
+	}
+
+	if comp.Pattern != "" {
+		pattern, err := comp.compilePattern()
+		if err != nil {
+			return fmt.Errorf("field '%s' has an invalid pattern: %w", f.Name, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("field '%s' does not match the pattern '%s'", f.Name, comp.Pattern)
+		}
+	}
+
+	if comp.Checksum != nil {
+		if err := comp.Checksum.ValidateChecksum(value); err != nil {
+			return fmt.Errorf("field '%s' has an invalid checksum: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFormat checks whether the value is one of the enum values and of the field's type, if set. Unlike the
+// value composition, this also applies to placeholders.
+func (f CredentialField) validateFormat(value string) error {
+	if len(f.Enum) > 0 && !containsEnumValue(f.Enum, value) {
+		return fmt.Errorf("field '%s' should be one of '%s'", f.Name, strings.Join(f.Enum, "', '"))
+	}
+
+	if err := f.Type.validate(value); err != nil {
+		return fmt.Errorf("field '%s' %w", f.Name, err)
+	}
+
+	return nil
+}
+
+// validateMetadata checks whether the type, default, placeholder and enum values of the field are consistent with
+// each other and with the value composition.
+func (f CredentialField) validateMetadata() error {
+	if !f.Type.isKnown() {
+		return fmt.Errorf("unknown type '%s'", f.Type)
+	}
+
+	for i, value := range f.Enum {
+		if containsEnumValue(f.Enum[:i], value) {
+			return fmt.Errorf("enum value '%s' is listed more than once", value)
+		}
+		if err := f.ValidateValue(value); err != nil {
+			return fmt.Errorf("enum value '%s' is invalid: %w", value, err)
+		}
+	}
+
+	if f.Default != "" {
+		if f.Secret {
+			return errors.New("secret fields can't have a default value")
+		}
+		if err := f.ValidateValue(f.Default); err != nil {
+			return fmt.Errorf("default value is invalid: %w", err)
+		}
+	}
+
+	if f.Placeholder != "" {
+		if err := f.validateFormat(f.Placeholder); err != nil {
+			return fmt.Errorf("placeholder is invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func containsEnumValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingPrefix returns the longest of the prefixes the value starts with. If no prefixes are specified, any value
+// matches with an empty prefix.
+func matchingPrefix(value string, prefixes []string) (string, bool) {
+	if len(prefixes) == 0 {
+		return "", true
+	}
+
+	var match string
+	var ok bool
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) && len(prefix) >= len(match) {
+			match = prefix
+			ok = true
+		}
+	}
+	return match, ok
+}
+
+// compilePattern compiles the pattern so that it has to match the whole value.
+func (v ValueComposition) compilePattern() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + v.Pattern + ")$")
+}
+
+// validateDefinition checks whether the composition itself is consistent, so that values can match it.
+func (v ValueComposition) validateDefinition() error {
+	if !v.Charset.isSet() {
+		return errors.New("no charset specified")
+	}
+	if v.Length > 0 && (v.MinLength > 0 || v.MaxLength > 0) {
+		return errors.New("a fixed length can't be combined with a minimum or maximum length")
+	}
+	if v.MaxLength > 0 && v.MinLength > v.MaxLength {
+		return errors.New("the minimum length is greater than the maximum length")
+	}
+	if v.Prefix != "" && len(v.Prefixes) > 0 {
+		return errors.New("a single prefix can't be combined with multiple prefixes")
+	}
+	if v.Pattern != "" {
+		if _, err := v.compilePattern(); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateFields checks whether all non-optional fields are set and whether all values match the composition of
+// their field. Values of fields that are not part of the credential type are not checked.
+func (c CredentialType) ValidateFields(fields map[sdk.FieldName]string) []error {
+	var errs []error
+	for _, field := range c.Fields {
+		if err := field.ValidateValue(fields[field.Name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Contains reports whether the character is part of the charset.
+func (c Charset) Contains(r rune) bool {
+	switch {
+	case c.Uppercase && r >= 'A' && r <= 'Z':
+		return true
+	case c.Lowercase && r >= 'a' && r <= 'z':
+		return true
+	case c.Digits && r >= '0' && r <= '9':
+		return true
+	case c.Symbols && strings.ContainsRune(symbolCharacters, r):
+		return true
+	}
+
+	for _, specific := range c.Specific {
+		if r == specific {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c Charset) isSet() bool {
+	return c.Uppercase || c.Lowercase || c.Digits || c.Symbols || len(c.Specific) > 0
+}
+
+// describe describes the charset, e.g. "uppercase letters, lowercase letters and digits".
+func (c Charset) describe() string {
+	var parts []string
+	if c.Uppercase {
+		parts = append(parts, "uppercase letters")
+	}
+	if c.Lowercase {
+		parts = append(parts, "lowercase letters")
+	}
+	if c.Digits {
+		parts = append(parts, "digits")
+	}
+	if c.Symbols {
+		parts = append(parts, "symbols")
+	}
+	for _, r := range c.Specific {
+		parts = append(parts, fmt.Sprintf("'%c'", r))
+	}
+
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}